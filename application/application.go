@@ -1,7 +1,10 @@
 package application
 
 import (
+	"os"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/corioders/gokit/log"
 )
@@ -9,16 +12,70 @@ import (
 type Application struct {
 	logger log.Logger
 
-	onStop []stopHandler
-	onStopMu  sync.Mutex
+	onStart   []startHandler
+	onStartMu sync.Mutex
+
+	onStop   []stopHandler
+	onStopMu sync.Mutex
+	stopping int32
+
+	healthChecks   []*healthCheck
+	healthChecksMu sync.Mutex
+
+	shutdownTimeout time.Duration
+	signals         []os.Signal
+}
+
+type options struct {
+	shutdownTimeout time.Duration
+	signals         []os.Signal
+}
+
+// Option configures Application created by New.
+type Option func(o *options)
+
+// DefaultShutdownTimeout is the time Run waits for Stop to finish, if no WithShutdownTimeout option is provided.
+const DefaultShutdownTimeout = 30 * time.Second
+
+// WithShutdownTimeout sets overall deadline for stopping application in Run,
+// timeout <= 0 means that Run waits for Stop as long as it takes.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.shutdownTimeout = timeout
+	}
 }
 
-func New(logger log.Logger) *Application {
+// WithSignals sets signals on which Run starts stopping the application,
+// by default these are os.Interrupt and syscall.SIGTERM.
+func WithSignals(signals ...os.Signal) Option {
+	return func(o *options) {
+		o.signals = signals
+	}
+}
+
+func New(logger log.Logger, opts ...Option) *Application {
+	o := &options{
+		shutdownTimeout: DefaultShutdownTimeout,
+		signals:         []os.Signal{os.Interrupt, syscall.SIGTERM},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	return &Application{
 		logger: logger,
 
-		onStop: make([]stopHandler, 0),
-		onStopMu:  sync.Mutex{},
+		onStart:   make([]startHandler, 0),
+		onStartMu: sync.Mutex{},
+
+		onStop:   make([]stopHandler, 0),
+		onStopMu: sync.Mutex{},
+
+		healthChecks:   make([]*healthCheck, 0),
+		healthChecksMu: sync.Mutex{},
+
+		shutdownTimeout: o.shutdownTimeout,
+		signals:         o.signals,
 	}
 }
 
@@ -0,0 +1,201 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/corioders/gokit/errors"
+)
+
+type healthCheckFunc func(ctx context.Context) error
+type healthCheck struct {
+	fn       healthCheckFunc
+	name     string
+	timeout  time.Duration
+	critical bool
+	cacheTTL time.Duration
+
+	resultMu sync.Mutex
+	result   *HealthCheckResult
+}
+
+type healthCheckOptions struct {
+	timeout  time.Duration
+	critical bool
+	cacheTTL time.Duration
+}
+
+// HealthCheckOption configures health check registered with RegisterHealthCheck.
+type HealthCheckOption func(o *healthCheckOptions)
+
+// DefaultHealthCheckTimeout is the maximum time health check can take, if no WithHealthCheckTimeout option is provided.
+const DefaultHealthCheckTimeout = 5 * time.Second
+
+// WithHealthCheckTimeout sets maximum time health check can take, after that it is reported as failing.
+func WithHealthCheckTimeout(timeout time.Duration) HealthCheckOption {
+	return func(o *healthCheckOptions) {
+		o.timeout = timeout
+	}
+}
+
+// Critical marks health check as critical, application is failing when critical check fails,
+// failure of non-critical check only degrades it.
+func Critical() HealthCheckOption {
+	return func(o *healthCheckOptions) {
+		o.critical = true
+	}
+}
+
+// WithHealthCheckCacheTTL sets time for which result of health check is reused instead of executing check again.
+func WithHealthCheckCacheTTL(ttl time.Duration) HealthCheckOption {
+	return func(o *healthCheckOptions) {
+		o.cacheTTL = ttl
+	}
+}
+
+type HealthStatus string
+
+const (
+	HealthStatusOK       HealthStatus = "ok"
+	HealthStatusDegraded HealthStatus = "degraded"
+	HealthStatusFailing  HealthStatus = "failing"
+)
+
+var (
+	ErrHealthCheckTimeout = errors.New("Health check timed out")
+)
+
+type HealthCheckResult struct {
+	Name      string        `json:"name"`
+	Status    HealthStatus  `json:"status"`
+	Critical  bool          `json:"critical"`
+	Error     string        `json:"error,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	CheckedAt time.Time     `json:"checkedAt"`
+}
+
+type HealthReport struct {
+	Status   HealthStatus         `json:"status"`
+	Stopping bool                 `json:"stopping,omitempty"`
+	Checks   []*HealthCheckResult `json:"checks"`
+}
+
+// RegisterHealthCheck registers function that reports whether component of application is healthy.
+func (a *Application) RegisterHealthCheck(name string, fn healthCheckFunc, options ...HealthCheckOption) {
+	o := &healthCheckOptions{timeout: DefaultHealthCheckTimeout}
+	for _, option := range options {
+		option(o)
+	}
+
+	a.healthChecksMu.Lock()
+	defer a.healthChecksMu.Unlock()
+	a.healthChecks = append(a.healthChecks, &healthCheck{
+		fn:       fn,
+		name:     name,
+		timeout:  o.timeout,
+		critical: o.critical,
+		cacheTTL: o.cacheTTL,
+	})
+}
+
+// Health executes registered health checks concurrently and returns aggregated report.
+func (a *Application) Health(ctx context.Context) *HealthReport {
+	a.healthChecksMu.Lock()
+	checks := make([]*healthCheck, len(a.healthChecks))
+	copy(checks, a.healthChecks)
+	a.healthChecksMu.Unlock()
+
+	report := &HealthReport{
+		Status: HealthStatusOK,
+		Checks: make([]*HealthCheckResult, len(checks)),
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(checks))
+	for i, check := range checks {
+		go func(i int, check *healthCheck) {
+			defer wg.Done()
+			report.Checks[i] = check.check(ctx)
+		}(i, check)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status == HealthStatusOK {
+			continue
+		}
+
+		if result.Critical {
+			report.Status = HealthStatusFailing
+		} else if report.Status == HealthStatusOK {
+			report.Status = HealthStatusDegraded
+		}
+	}
+
+	return report
+}
+
+// Readiness is the same as Health, except that application is reported as failing once it starts stopping.
+func (a *Application) Readiness(ctx context.Context) *HealthReport {
+	report := a.Health(ctx)
+	if a.IsStopping() {
+		report.Status = HealthStatusFailing
+		report.Stopping = true
+	}
+
+	return report
+}
+
+// IsStopping reports whether Stop was called.
+func (a *Application) IsStopping() bool {
+	return atomic.LoadInt32(&a.stopping) == 1
+}
+
+func (hc *healthCheck) check(ctx context.Context) *HealthCheckResult {
+	hc.resultMu.Lock()
+	defer hc.resultMu.Unlock()
+
+	if hc.result != nil && time.Since(hc.result.CheckedAt) < hc.cacheTTL {
+		return hc.result
+	}
+
+	if hc.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, hc.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+
+	// Check can be abandoned, so goroutine must not reference hc.
+	fn := hc.fn
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = errors.WithMessage(ErrHealthCheckTimeout, fmt.Sprintf("%s, %v", hc.name, ctx.Err()))
+	}
+
+	result := &HealthCheckResult{
+		Name:      hc.name,
+		Status:    HealthStatusOK,
+		Critical:  hc.critical,
+		Duration:  time.Since(start),
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Status = HealthStatusFailing
+		result.Error = errors.GetErrorNoStack(err)
+	}
+
+	hc.result = result
+	return result
+}
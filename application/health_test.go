@@ -0,0 +1,102 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/corioders/gokit/log"
+)
+
+func TestHealth(t *testing.T) {
+	t.Run("status", func(t *testing.T) {
+		application := New(log.New(io.Discard, ""))
+
+		application.RegisterHealthCheck("ok", func(ctx context.Context) error {
+			return nil
+		}, Critical())
+
+		report := application.Health(context.Background())
+		if report.Status != HealthStatusOK {
+			t.Fatal("Expected health status to be ok, but got:", report.Status)
+		}
+
+		application.RegisterHealthCheck("nonCritical", func(ctx context.Context) error {
+			return fmt.Errorf("test error")
+		})
+
+		report = application.Health(context.Background())
+		if report.Status != HealthStatusDegraded {
+			t.Fatal("Expected health status to be degraded when non-critical check fails, but got:", report.Status)
+		}
+
+		application.RegisterHealthCheck("critical", func(ctx context.Context) error {
+			return fmt.Errorf("test error")
+		}, Critical())
+
+		report = application.Health(context.Background())
+		if report.Status != HealthStatusFailing {
+			t.Fatal("Expected health status to be failing when critical check fails, but got:", report.Status)
+		}
+		if len(report.Checks) != 3 || report.Checks[2].Error != "test error" {
+			t.Fatal("Expected report to contain results of all checks in registration order, but got:", report.Checks)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		application := New(log.New(io.Discard, ""))
+
+		application.RegisterHealthCheck("hung", func(ctx context.Context) error {
+			<-ctx.Done()
+			time.Sleep(time.Second)
+			return nil
+		}, Critical(), WithHealthCheckTimeout(10*time.Millisecond))
+
+		report := application.Health(context.Background())
+		if report.Status != HealthStatusFailing {
+			t.Fatal("Expected health status to be failing when critical check times out, but got:", report.Status)
+		}
+	})
+
+	t.Run("cache", func(t *testing.T) {
+		application := New(log.New(io.Discard, ""))
+
+		calls := 0
+		application.RegisterHealthCheck("cached", func(ctx context.Context) error {
+			calls++
+			return nil
+		}, WithHealthCheckCacheTTL(time.Hour))
+
+		application.Health(context.Background())
+		application.Health(context.Background())
+		if calls != 1 {
+			t.Fatal("Expected cached health check to be executed once, but got:", calls)
+		}
+	})
+
+	t.Run("readiness", func(t *testing.T) {
+		application := New(log.New(io.Discard, ""))
+
+		report := application.Readiness(context.Background())
+		if report.Status != HealthStatusOK {
+			t.Fatal("Expected readiness status to be ok before stopping, but got:", report.Status)
+		}
+
+		err := application.Stop()
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		report = application.Readiness(context.Background())
+		if report.Status != HealthStatusFailing || !report.Stopping {
+			t.Fatal("Expected readiness status to be failing after stopping, but got:", report.Status)
+		}
+
+		report = application.Health(context.Background())
+		if report.Status != HealthStatusOK {
+			t.Fatal("Expected health status not to be affected by stopping, but got:", report.Status)
+		}
+	})
+}
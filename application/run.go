@@ -0,0 +1,61 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/corioders/gokit/errors"
+)
+
+var (
+	ErrShutdownTimeout = errors.New("Application did not stop before shutdown timeout")
+)
+
+// Run starts the application and blocks until one of termination signals is received or ctx is done,
+// then it stops the application. If starting fails, stop handlers registered before Start are executed
+// and Run returns the start error combined with errors of stopping.
+// Stopping is bounded by shutdown timeout, if it is exceeded Run returns ErrShutdownTimeout combined with errors of stopping
+// and stop handlers that are still running are abandoned. Signals received while stopping are not handled by Run,
+// so the second signal terminates the process.
+func (a *Application) Run(ctx context.Context) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, a.signals...)
+
+	err := a.Start(ctx)
+	if err != nil {
+		signal.Stop(signals)
+		// Start rolls back only handlers registered by start hooks, resources acquired before must be released too.
+		return errors.Append(err, a.stopWithTimeout())
+	}
+
+	a.logger.Info("Running...")
+	select {
+	case sig := <-signals:
+		a.logger.Info(fmt.Sprintf("Received signal %v", sig))
+	case <-ctx.Done():
+		a.logger.Info(fmt.Sprintf("Context done: %v", ctx.Err()))
+	}
+
+	// Signals received while stopping get their default behaviour, so operator can force the process to exit.
+	signal.Stop(signals)
+	return a.stopWithTimeout()
+}
+
+func (a *Application) stopWithTimeout() error {
+	if a.shutdownTimeout <= 0 {
+		return a.Stop()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout)
+	defer cancel()
+
+	err := a.StopContext(ctx)
+	if ctx.Err() != nil {
+		// Errors of handlers that finished before the timeout are still reported.
+		return errors.Append(errors.WithMessage(ErrShutdownTimeout, fmt.Sprintf("timeout %v exceeded", a.shutdownTimeout)), err)
+	}
+
+	return err
+}
@@ -0,0 +1,104 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/corioders/gokit/errors"
+	"github.com/corioders/gokit/log"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("context done", func(t *testing.T) {
+		application := New(log.New(io.Discard, ""))
+
+		stopFuncCalled := false
+		application.RegisterOnStop("stopTest", func() error {
+			stopFuncCalled = true
+			return nil
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := application.Run(ctx)
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		if !stopFuncCalled {
+			t.Fatal("Expected the stop func to be called")
+		}
+	})
+
+	t.Run("with error", func(t *testing.T) {
+		application := New(log.New(io.Discard, ""))
+
+		expectedErr := fmt.Errorf("test error")
+		application.RegisterOnStop("stopTest", func() error {
+			return expectedErr
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := application.Run(ctx)
+		if err != expectedErr {
+			t.Fatal("Expected err returned by Run to be the same as error returned by StopFunc, but got:", err)
+		}
+	})
+
+	t.Run("start error", func(t *testing.T) {
+		application := New(log.New(io.Discard, ""))
+
+		stopFuncCalled := false
+		application.RegisterOnStop("registeredBefore", func() error {
+			stopFuncCalled = true
+			return nil
+		})
+
+		expectedErr := fmt.Errorf("test error")
+		application.RegisterOnStart("failing", func(ctx context.Context) error {
+			return expectedErr
+		})
+
+		err := application.Run(context.Background())
+		if !errors.Is(err, expectedErr) {
+			t.Fatal("Expected err returned by Run to be the start error, but got:", err)
+		}
+
+		if !stopFuncCalled {
+			t.Fatal("Expected stop handler registered before Start to be called when Start fails")
+		}
+	})
+
+	t.Run("shutdown timeout", func(t *testing.T) {
+		application := New(log.New(io.Discard, ""), WithShutdownTimeout(10*time.Millisecond))
+
+		unblock := make(chan struct{})
+		defer close(unblock)
+		application.RegisterOnStop("stopTest", func() error {
+			<-unblock
+			return nil
+		})
+		// Registered last, so it is stopped first and finishes before the timeout.
+		expectedErr := fmt.Errorf("test error")
+		application.RegisterOnStop("failing", func() error {
+			return expectedErr
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := application.Run(ctx)
+		if !errors.Is(err, ErrShutdownTimeout) {
+			t.Fatal("Expected ErrShutdownTimeout, but got:", err)
+		}
+		if !errors.Is(err, expectedErr) {
+			t.Fatal("Expected error of handler that finished before the timeout, but got:", err)
+		}
+	})
+}
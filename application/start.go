@@ -0,0 +1,78 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/corioders/gokit/errors"
+)
+
+type startFunc func(ctx context.Context) error
+type startHandler struct {
+	fn   startFunc
+	name string
+}
+
+// RegisterOnStart registers function that should be executed when application is starting.
+// Stop handlers registered while fn is executed are treated as belonging to it.
+func (a *Application) RegisterOnStart(name string, fn startFunc) {
+	a.onStartMu.Lock()
+	defer a.onStartMu.Unlock()
+	a.onStart = append(a.onStart, startHandler{name: name, fn: fn})
+}
+
+// Start executes registered start hooks in registration order.
+// If one of them fails, stop handlers registered by already executed hooks, including the failed one,
+// are executed and removed from the application, so half-started application releases its resources.
+// Returned error combines start error with errors of rollback.
+// Start hooks must not call RegisterOnStart.
+func (a *Application) Start(ctx context.Context) error {
+	a.logger.Info("Starting...")
+
+	a.onStartMu.Lock()
+	defer a.onStartMu.Unlock()
+
+	a.onStopMu.Lock()
+	rollbackIndex := len(a.onStop)
+	a.onStopMu.Unlock()
+
+	for _, handler := range a.onStart {
+		a.logger.Info(fmt.Sprintf("Starting %s...", handler.name))
+
+		err := handler.fn(ctx)
+		if err != nil {
+			a.logger.Error(fmt.Sprintf("Starting %s failed: %v", handler.name, err))
+			return errors.Append(err, a.rollback(rollbackIndex))
+		}
+
+		a.logger.Info(fmt.Sprintf("Started successfully %s...", handler.name))
+	}
+
+	a.logger.Info("Started successfully...")
+	return nil
+}
+
+// rollback executes and removes stop handlers registered after rollbackIndex.
+func (a *Application) rollback(rollbackIndex int) error {
+	a.logger.Info("Rolling back start...")
+
+	ctx := context.Background()
+	if a.shutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.shutdownTimeout)
+		defer cancel()
+	}
+
+	a.onStopMu.Lock()
+	defer a.onStopMu.Unlock()
+
+	err := a.stopHandlers(ctx, a.onStop[rollbackIndex:])
+	a.onStop = a.onStop[:rollbackIndex]
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Rolling back start failed: %v", err))
+		return err
+	}
+
+	a.logger.Info("Rolled back start successfully...")
+	return nil
+}
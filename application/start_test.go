@@ -0,0 +1,98 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/corioders/gokit/log"
+	"github.com/corioders/gokit/log/logtest"
+)
+
+func TestStart(t *testing.T) {
+	t.Run("no error", func(t *testing.T) {
+		application := New(log.New(io.Discard, ""))
+
+		order := make([]string, 0)
+		application.RegisterOnStart("first", func(ctx context.Context) error {
+			order = append(order, "first")
+			return nil
+		})
+		application.RegisterOnStart("second", func(ctx context.Context) error {
+			order = append(order, "second")
+			return nil
+		})
+
+		err := application.Start(context.Background())
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		if strings.Join(order, ",") != "first,second" {
+			t.Fatal("Expected start hooks to be executed in registration order, but got:", order)
+		}
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		logger := logtest.New(t)
+		application := New(logger)
+
+		stopRegisteredBeforeCalled := false
+		application.RegisterOnStop("registeredBefore", func() error {
+			stopRegisteredBeforeCalled = true
+			return nil
+		})
+
+		stopFirstCalled := false
+		application.RegisterOnStart("first", func(ctx context.Context) error {
+			application.RegisterOnStop("first", func() error {
+				stopFirstCalled = true
+				return nil
+			})
+			return nil
+		})
+
+		expectedErr := fmt.Errorf("test error")
+		application.RegisterOnStart("second", func(ctx context.Context) error {
+			return expectedErr
+		})
+
+		thirdCalled := false
+		application.RegisterOnStart("third", func(ctx context.Context) error {
+			thirdCalled = true
+			return nil
+		})
+
+		err := application.Start(context.Background())
+		if err != expectedErr {
+			t.Fatal("Expected err returned by Start to be the same as error returned by start hook, but got:", err)
+		}
+
+		if !stopFirstCalled {
+			t.Fatal("Expected stop handler registered by started hook to be called")
+		}
+		if thirdCalled {
+			t.Fatal("Expected start hook after failed one not to be called")
+		}
+		if stopRegisteredBeforeCalled {
+			t.Fatal("Expected stop handler registered before Start not to be called by rollback")
+		}
+		logger.AssertMessage("Starting second failed: test error")
+		logger.AssertMessage("Rolled back start successfully...")
+
+		stopFirstCalled = false
+		err = application.Stop()
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		if stopFirstCalled {
+			t.Fatal("Expected rolled back stop handler not to be called again by Stop")
+		}
+		if !stopRegisteredBeforeCalled {
+			t.Fatal("Expected stop handler registered before Start to be called by Stop")
+		}
+	})
+}
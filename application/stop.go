@@ -1,40 +1,279 @@
 package application
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/corioders/gokit/errors"
+)
 
 type StopRegistrar interface {
 	RegisterOnStop(name string, fn stopFunc)
+	RegisterOnStopContext(name string, fn stopContextFunc, options ...StopOption) error
 }
 
 type stopFunc func() error
+type stopContextFunc func(ctx context.Context) error
 type stopHandler struct {
-	fn   stopFunc
-	name string
+	fn         stopContextFunc
+	name       string
+	timeout    time.Duration
+	dependsOn  []string
+	concurrent bool
+}
+
+type stopOptions struct {
+	timeout    time.Duration
+	dependsOn  []string
+	concurrent bool
+}
+
+// StopOption configures stop handler registered with RegisterOnStopContext.
+type StopOption func(o *stopOptions)
+
+// WithStopTimeout sets maximum time the stop handler can take,
+// after that it is reported as timed out and stopping proceeds to the next handler.
+func WithStopTimeout(timeout time.Duration) StopOption {
+	return func(o *stopOptions) {
+		o.timeout = timeout
+	}
+}
+
+// DependsOn declares names of stop handlers that the handler depends on,
+// handler is stopped before all of the handlers it depends on, names don't need to be registered yet.
+func DependsOn(names ...string) StopOption {
+	return func(o *stopOptions) {
+		o.dependsOn = append(o.dependsOn, names...)
+	}
 }
 
+// Concurrent allows the stop handler to run concurrently with other handlers,
+// it is still ordered with handlers declared by DependsOn, but not by registration order.
+func Concurrent() StopOption {
+	return func(o *stopOptions) {
+		o.concurrent = true
+	}
+}
+
+var (
+	ErrStopTimeout = errors.New("Stop handler timed out")
+	ErrStopCycle   = errors.New("Stop handlers dependency cycle")
+)
+
 // RegisterOnStop registers function that should be executed when application is stopping.
 func (a *Application) RegisterOnStop(name string, fn stopFunc) {
+	// Handler without dependencies cannot create a cycle.
+	_ = a.RegisterOnStopContext(name, func(ctx context.Context) error {
+		return fn()
+	})
+}
+
+// RegisterOnStopContext registers function that should be executed when application is stopping,
+// ctx passed to fn is done when handler's timeout or stop deadline is exceeded.
+// If declared dependencies create a cycle handler is not registered and ErrStopCycle is returned.
+func (a *Application) RegisterOnStopContext(name string, fn stopContextFunc, options ...StopOption) error {
+	o := &stopOptions{}
+	for _, option := range options {
+		option(o)
+	}
+
 	a.onStopMu.Lock()
 	defer a.onStopMu.Unlock()
-	a.onStop = append(a.onStop, stopHandler{name: name, fn: fn})
+
+	for _, dependency := range o.dependsOn {
+		path := stopDependencyPath(a.onStop, dependency, name)
+		if path != nil {
+			return errors.WithMessage(ErrStopCycle, strings.Join(append([]string{name}, path...), " -> "))
+		}
+	}
+
+	a.onStop = append(a.onStop, stopHandler{name: name, fn: fn, timeout: o.timeout, dependsOn: o.dependsOn, concurrent: o.concurrent})
+	return nil
+}
+
+// stopDependencyPath returns path of handler names leading from handler named from to handler named to,
+// or nil if there is no such path.
+func stopDependencyPath(handlers []stopHandler, from, to string) []string {
+	visited := make(map[string]struct{})
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		if name == to {
+			return []string{name}
+		}
+		if _, ok := visited[name]; ok {
+			return nil
+		}
+		visited[name] = struct{}{}
+
+		for _, handler := range handlers {
+			if handler.name != name {
+				continue
+			}
+
+			for _, dependency := range handler.dependsOn {
+				path := visit(dependency)
+				if path != nil {
+					return append([]string{name}, path...)
+				}
+			}
+		}
+		return nil
+	}
+
+	return visit(from)
 }
 
+// Stop is the same as StopContext(context.Background()).
 func (a *Application) Stop() error {
+	return a.StopContext(context.Background())
+}
+
+// StopContext executes registered stop handlers, ctx is the deadline for stopping whole application.
+// Handlers are executed one after another in reverse order of registration, handler is executed after all handlers
+// that depend on it are finished even if it was registered later. Handlers registered with Concurrent option
+// are executed as soon as handlers depending on them are finished.
+// Handlers that exceed their timeout or ctx deadline are abandoned and reported with ErrStopTimeout.
+// Failed handler doesn't stop execution of the others, if more than one handler fails all errors are returned combined.
+func (a *Application) StopContext(ctx context.Context) error {
+	atomic.StoreInt32(&a.stopping, 1)
 	a.logger.Info("Stopping...")
 
 	a.onStopMu.Lock()
 	defer a.onStopMu.Unlock()
-	for _, handler := range a.onStop {
-		a.logger.Info(fmt.Sprintf("Stopping %s...", handler.name))
 
-		err := handler.fn()
-		if err != nil {
-			return err
-		}
-
-		a.logger.Info(fmt.Sprintf("Stopped successfully %s...", handler.name))
+	err := a.stopHandlers(ctx, a.onStop)
+	if err != nil {
+		return err
 	}
 
 	a.logger.Info("Stopped successfully...")
 	return nil
 }
+
+func (a *Application) stopHandlers(ctx context.Context, handlers []stopHandler) error {
+	done := make([]chan struct{}, len(handlers))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	// previous is index of not concurrent handler that must finish before not concurrent handler is executed.
+	previous := make([]int, len(handlers))
+	last := -1
+	for _, i := range stopOrder(handlers) {
+		previous[i] = -1
+		if !handlers[i].concurrent {
+			previous[i] = last
+			last = i
+		}
+	}
+
+	errs := make([]error, len(handlers))
+	wg := sync.WaitGroup{}
+	wg.Add(len(handlers))
+	for i := range handlers {
+		go func(i int) {
+			defer wg.Done()
+			defer close(done[i])
+
+			handler := &handlers[i]
+			for j := range handlers {
+				if handlers[j].isDependentOn(handler.name) {
+					<-done[j]
+				}
+			}
+			if previous[i] != -1 {
+				<-done[previous[i]]
+			}
+
+			a.logger.Info(fmt.Sprintf("Stopping %s...", handler.name))
+			err := handler.stop(ctx)
+			if err != nil {
+				a.logger.Error(fmt.Sprintf("Stopping %s failed: %v", handler.name, err))
+				errs[i] = err
+				return
+			}
+
+			a.logger.Info(fmt.Sprintf("Stopped successfully %s...", handler.name))
+		}(i)
+	}
+	wg.Wait()
+
+	return errors.Append(nil, errs...)
+}
+
+// stopOrder returns indexes of handlers in order of stopping, that is reverse order of registration
+// changed only where handler registered earlier depends on handler registered later.
+func stopOrder(handlers []stopHandler) []int {
+	// dependents counts not yet ordered handlers that depend on handler.
+	dependents := make([]int, len(handlers))
+	for i := range handlers {
+		for j := range handlers {
+			if handlers[j].isDependentOn(handlers[i].name) {
+				dependents[i]++
+			}
+		}
+	}
+
+	ordered := make([]bool, len(handlers))
+	order := make([]int, 0, len(handlers))
+	for len(order) < len(handlers) {
+		// Dependencies are acyclic, so there is always handler without not ordered dependents.
+		next := -1
+		for i := len(handlers) - 1; i >= 0; i-- {
+			if !ordered[i] && dependents[i] == 0 {
+				next = i
+				break
+			}
+		}
+
+		ordered[next] = true
+		order = append(order, next)
+		for i := range handlers {
+			if handlers[next].isDependentOn(handlers[i].name) {
+				dependents[i]--
+			}
+		}
+	}
+
+	return order
+}
+
+func (h *stopHandler) isDependentOn(name string) bool {
+	for _, dependency := range h.dependsOn {
+		if dependency == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *stopHandler) stop(ctx context.Context) error {
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+
+	if ctx.Err() != nil {
+		return errors.WithMessage(ErrStopTimeout, fmt.Sprintf(`%s not started, %v`, h.name, ctx.Err()))
+	}
+
+	// Handler can be abandoned, so goroutine must not reference h.
+	fn := h.fn
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return errors.WithMessage(ErrStopTimeout, fmt.Sprintf(`%s, %v`, h.name, ctx.Err()))
+	}
+}
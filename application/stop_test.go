@@ -1,10 +1,15 @@
 package application
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/corioders/gokit/errors"
 	"github.com/corioders/gokit/log"
 )
 
@@ -47,3 +52,236 @@ func TestStop(t *testing.T) {
 		}
 	})
 }
+
+func TestStopContext(t *testing.T) {
+	t.Run("handler timeout", func(t *testing.T) {
+		application := New(log.New(io.Discard, ""))
+
+		// Registered first, so it is stopped after the hung handler.
+		nextCalled := false
+		application.RegisterOnStop("next", func() error {
+			nextCalled = true
+			return nil
+		})
+
+		unblock := make(chan struct{})
+		defer close(unblock)
+		application.RegisterOnStopContext("hung", func(ctx context.Context) error {
+			<-unblock
+			return nil
+		}, WithStopTimeout(10*time.Millisecond))
+
+		err := application.StopContext(context.Background())
+		if !errors.Is(err, ErrStopTimeout) {
+			t.Fatal("Expected ErrStopTimeout, but got:", err)
+		}
+
+		if !nextCalled {
+			t.Fatal("Expected stop handler stopped after timed out one to be called")
+		}
+	})
+
+	t.Run("handler receives deadline", func(t *testing.T) {
+		application := New(log.New(io.Discard, ""))
+
+		hasDeadline := false
+		application.RegisterOnStopContext("deadline", func(ctx context.Context) error {
+			_, hasDeadline = ctx.Deadline()
+			return nil
+		}, WithStopTimeout(time.Second))
+
+		err := application.StopContext(context.Background())
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		if !hasDeadline {
+			t.Fatal("Expected ctx passed to stop handler to have deadline")
+		}
+	})
+
+	t.Run("global deadline", func(t *testing.T) {
+		application := New(log.New(io.Discard, ""))
+
+		notStartedCalled := false
+		application.RegisterOnStopContext("blocking", func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		}, DependsOn("notStarted"))
+		application.RegisterOnStop("notStarted", func() error {
+			notStartedCalled = true
+			return nil
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := application.StopContext(ctx)
+		if !errors.Is(err, ErrStopTimeout) {
+			t.Fatal("Expected ErrStopTimeout, but got:", err)
+		}
+
+		if notStartedCalled {
+			t.Fatal("Expected stop handler not to be called after global deadline is exceeded")
+		}
+	})
+}
+
+func TestStopDependencies(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		application := New(log.New(io.Discard, ""))
+
+		orderMu := sync.Mutex{}
+		order := make([]string, 0)
+		stopFunc := func(name string) stopContextFunc {
+			return func(ctx context.Context) error {
+				orderMu.Lock()
+				defer orderMu.Unlock()
+				order = append(order, name)
+				return nil
+			}
+		}
+
+		mustRegister := func(name string, options ...StopOption) {
+			err := application.RegisterOnStopContext(name, stopFunc(name), options...)
+			if err != nil {
+				t.Fatal("Expected no error while registering stop handler, but got:", err)
+			}
+		}
+		mustRegister("db")
+		mustRegister("cache", DependsOn("db"))
+		mustRegister("server", DependsOn("db", "cache"))
+
+		err := application.Stop()
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		expectedOrder := []string{"server", "cache", "db"}
+		if strings.Join(order, ",") != strings.Join(expectedOrder, ",") {
+			t.Fatalf("Expected stop order to be %v, but got %v", expectedOrder, order)
+		}
+	})
+
+	t.Run("reverse registration order", func(t *testing.T) {
+		application := New(log.New(io.Discard, ""))
+
+		order := make([]string, 0)
+		for _, name := range []string{"telemetry", "db", "server"} {
+			name := name
+			application.RegisterOnStop(name, func() error {
+				order = append(order, name)
+				return nil
+			})
+		}
+
+		err := application.Stop()
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		expectedOrder := []string{"server", "db", "telemetry"}
+		if strings.Join(order, ",") != strings.Join(expectedOrder, ",") {
+			t.Fatalf("Expected stop order to be %v, but got %v", expectedOrder, order)
+		}
+	})
+
+	t.Run("dependency on later handler", func(t *testing.T) {
+		application := New(log.New(io.Discard, ""))
+
+		order := make([]string, 0)
+		stop := func(name string) stopContextFunc {
+			return func(ctx context.Context) error {
+				order = append(order, name)
+				return nil
+			}
+		}
+		application.RegisterOnStopContext("first", stop("first"))
+		application.RegisterOnStopContext("queue", stop("queue"), DependsOn("worker"))
+		application.RegisterOnStopContext("worker", stop("worker"))
+
+		err := application.Stop()
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		expectedOrder := []string{"queue", "worker", "first"}
+		if strings.Join(order, ",") != strings.Join(expectedOrder, ",") {
+			t.Fatalf("Expected stop order to be %v, but got %v", expectedOrder, order)
+		}
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		application := New(log.New(io.Discard, ""))
+
+		// Both handlers wait for each other, so they finish only when they run concurrently.
+		first, second := make(chan struct{}), make(chan struct{})
+		application.RegisterOnStopContext("first", func(ctx context.Context) error {
+			close(first)
+			<-second
+			return nil
+		}, Concurrent(), WithStopTimeout(time.Second))
+		application.RegisterOnStopContext("second", func(ctx context.Context) error {
+			close(second)
+			<-first
+			return nil
+		}, Concurrent(), WithStopTimeout(time.Second))
+
+		err := application.Stop()
+		if err != nil {
+			t.Fatal("Expected concurrent handlers to run concurrently, but got:", err)
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		application := New(log.New(io.Discard, ""))
+		noop := func(ctx context.Context) error { return nil }
+
+		err := application.RegisterOnStopContext("a", noop, DependsOn("b"))
+		if err != nil {
+			t.Fatal("Expected no error while registering stop handler, but got:", err)
+		}
+		err = application.RegisterOnStopContext("b", noop, DependsOn("c"))
+		if err != nil {
+			t.Fatal("Expected no error while registering stop handler, but got:", err)
+		}
+
+		err = application.RegisterOnStopContext("c", noop, DependsOn("a"))
+		if !errors.Is(err, ErrStopCycle) {
+			t.Fatal("Expected ErrStopCycle, but got:", err)
+		}
+
+		err = application.RegisterOnStopContext("self", noop, DependsOn("self"))
+		if !errors.Is(err, ErrStopCycle) {
+			t.Fatal("Expected ErrStopCycle for handler depending on itself, but got:", err)
+		}
+	})
+
+	t.Run("continue on error", func(t *testing.T) {
+		application := New(log.New(io.Discard, ""))
+
+		firstErr := fmt.Errorf("first error")
+		secondErr := fmt.Errorf("second error")
+		dbStopped := false
+
+		application.RegisterOnStop("db", func() error {
+			dbStopped = true
+			return nil
+		})
+		application.RegisterOnStopContext("first", func(ctx context.Context) error {
+			return firstErr
+		}, DependsOn("db"))
+		application.RegisterOnStopContext("second", func(ctx context.Context) error {
+			return secondErr
+		}, DependsOn("db"))
+
+		err := application.Stop()
+		if !errors.Is(err, firstErr) || !errors.Is(err, secondErr) {
+			t.Fatal("Expected err returned by Stop to contain errors of all failed handlers, but got:", err)
+		}
+
+		if !dbStopped {
+			t.Fatal("Expected handler to be stopped even if handlers depending on it failed")
+		}
+	})
+}
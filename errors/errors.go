@@ -8,6 +8,8 @@ type internalError struct {
 	message    string
 	wrappedErr error
 	stack      stack
+	fields     []Field
+	kind       Kind
 }
 
 func (e *internalError) Error() string {
@@ -141,20 +143,28 @@ func IgnoreStack(err error) error {
 	return e
 }
 
-// GetErrorMessage returns error message of wrapped error
-// note that err should be possessed from New or WithMessage func.
+// GetErrorMessage returns error message of wrapped error,
+// for errors not created by this package it returns err.Error(), as they have no stack.
 func GetErrorNoStack(err error) string {
+	if multiErr, ok := err.(*multiError); ok {
+		return multiErr.error(false)
+	}
+
 	e, ok := err.(*internalError)
 	if !ok {
-		return "GetErrorNoStack in gokit/errors, err not of type *internalError"
+		return err.Error()
 	}
 
 	return e.error(false)
 }
 
-// GetErrorStack returns stack of err
-// note that err should be possessed from New or WithMessage func.
+// GetErrorStack returns stack of err, for errors combined by Append it returns stacks of all of them
+// note that err should be possessed from New, WithMessage or Append func.
 func GetErrorStack(err error) string {
+	if _, ok := err.(*multiError); ok {
+		return FormatStack(err, &StackFormatOptions{Color: true, Source: defaultSource})
+	}
+
 	e, ok := err.(*internalError)
 	if !ok {
 		return "GetErrorStack in gokit/errors, err not of type *internalError"
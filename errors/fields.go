@@ -0,0 +1,28 @@
+package errors
+
+// Field is key-value pair attached to error by With.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// With returns err with key-value field attached and internally calls WithStack.
+func With(err error, key string, value interface{}) error {
+	e := withStack(err)
+	e.fields = append(e.fields, Field{Key: key, Value: value})
+	return e
+}
+
+// Fields returns fields attached by With to err and all errors in its chain,
+// fields attached first are first.
+func Fields(err error) []Field {
+	var fields []Field
+	for err != nil {
+		if e, ok := err.(*internalError); ok && len(e.fields) != 0 {
+			fields = append(append(make([]Field, 0, len(e.fields)+len(fields)), e.fields...), fields...)
+		}
+		err = Unwrap(err)
+	}
+
+	return fields
+}
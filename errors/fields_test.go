@@ -0,0 +1,38 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWith(t *testing.T) {
+	t.Run("fields", func(t *testing.T) {
+		wrappedErr := New(message)
+		err := With(WithMessage(With(wrappedErr, "first", 1), message), "second", "2")
+
+		if !Is(err, wrappedErr) {
+			t.Fatal("Expected With to wrap err")
+		}
+
+		fields := Fields(err)
+		if len(fields) != 2 {
+			t.Fatal("Expected Fields to return fields attached to all errors in chain, but got:", fields)
+		}
+		if fields[0] != (Field{Key: "first", Value: 1}) || fields[1] != (Field{Key: "second", Value: "2"}) {
+			t.Fatal("Expected Fields to return fields in order they were attached, but got:", fields)
+		}
+	})
+
+	t.Run("message", func(t *testing.T) {
+		err := With(New(message), "key", "value")
+		if GetErrorNoStack(err) != message {
+			t.Fatal("Expected With not to change error message, but got:", GetErrorNoStack(err))
+		}
+	})
+
+	t.Run("no fields", func(t *testing.T) {
+		if Fields(fmt.Errorf(message)) != nil {
+			t.Fatal("Expected Fields of error without fields to be nil")
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type errorJSON struct {
+	Message string                     `json:"message"`
+	Kind    string                     `json:"kind,omitempty"`
+	Fields  map[string]json.RawMessage `json:"fields,omitempty"`
+	Stack   []Frame                    `json:"stack,omitempty"`
+}
+
+type multiErrorJSON struct {
+	Message string            `json:"message"`
+	Errors  []json.RawMessage `json:"errors"`
+}
+
+// MarshalJSON implements Marshaler interface.
+func (e *internalError) MarshalJSON() ([]byte, error) {
+	ej := errorJSON{Message: e.error(false)}
+	if kind := KindOf(e); kind != KindUnknown {
+		ej.Kind = kind.String()
+	}
+
+	fields := Fields(e)
+	if len(fields) != 0 {
+		ej.Fields = make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			value, err := json.Marshal(field.Value)
+			if err != nil {
+				// Not every value can be represented in json, fallback to its string representation.
+				value, err = json.Marshal(fmt.Sprint(field.Value))
+				if err != nil {
+					return nil, err
+				}
+			}
+			ej.Fields[field.Key] = value
+		}
+	}
+
+	if e.stack != nil {
+		ej.Stack = e.stack.trimmedFrames()
+	}
+
+	return json.Marshal(ej)
+}
+
+// MarshalJSON implements Marshaler interface.
+func (e *multiError) MarshalJSON() ([]byte, error) {
+	mej := multiErrorJSON{
+		Message: e.error(false),
+		Errors:  make([]json.RawMessage, 0, len(e.errs)),
+	}
+
+	for _, err := range e.errs {
+		var data []byte
+		var marshalErr error
+		if marshaler, ok := err.(json.Marshaler); ok {
+			data, marshalErr = marshaler.MarshalJSON()
+		} else {
+			data, marshalErr = json.Marshal(errorJSON{Message: err.Error()})
+		}
+		if marshalErr != nil {
+			return nil, marshalErr
+		}
+
+		mej.Errors = append(mej.Errors, data)
+	}
+
+	return json.Marshal(mej)
+}
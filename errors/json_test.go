@@ -0,0 +1,85 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	t.Run("internalError", func(t *testing.T) {
+		err := With(New(message), "key", "value")
+
+		data, marshalErr := json.Marshal(err)
+		if marshalErr != nil {
+			t.Fatal("Error while marshaling error, error:", marshalErr)
+		}
+
+		ej := struct {
+			Message string            `json:"message"`
+			Fields  map[string]string `json:"fields"`
+			Stack   []Frame           `json:"stack"`
+		}{}
+		unmarshalErr := json.Unmarshal(data, &ej)
+		if unmarshalErr != nil {
+			t.Fatal("Error while unmarshaling error json, error:", unmarshalErr)
+		}
+
+		if ej.Message != message {
+			t.Fatal("Expected message in json to equal message, but got:", ej.Message)
+		}
+		if ej.Fields["key"] != "value" {
+			t.Fatal("Expected fields in json to contain attached field, but got:", ej.Fields)
+		}
+		if len(ej.Stack) == 0 || !strings.HasSuffix(ej.Stack[0].Func, "TestMarshalJSON.func1") {
+			t.Fatal("Expected first frame in json to be the function that created error, but got:", ej.Stack)
+		}
+	})
+
+	t.Run("multiError", func(t *testing.T) {
+		err := Append(New(message), fmt.Errorf(message))
+
+		data, marshalErr := json.Marshal(err)
+		if marshalErr != nil {
+			t.Fatal("Error while marshaling error, error:", marshalErr)
+		}
+
+		mej := struct {
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}{}
+		unmarshalErr := json.Unmarshal(data, &mej)
+		if unmarshalErr != nil {
+			t.Fatal("Error while unmarshaling error json, error:", unmarshalErr)
+		}
+
+		if len(mej.Errors) != 2 || mej.Errors[0].Message != message || mej.Errors[1].Message != message {
+			t.Fatal("Expected json to contain all appended errors, but got:", string(data))
+		}
+	})
+}
+
+func TestFormatStack(t *testing.T) {
+	t.Run("plain", func(t *testing.T) {
+		err := New(message)
+
+		stack := FormatStack(err, &StackFormatOptions{})
+		if strings.Contains(stack, "\x1b[") {
+			t.Fatal("Expected plain stack not to contain ANSI escape codes")
+		}
+
+		frames := Frames(err)
+		rows := strings.Split(stack, "\n")
+		if len(rows) != len(frames) || rows[0] != fmt.Sprintf("%s:%d", frames[0].File, frames[0].Line) {
+			t.Fatal("Expected plain stack without source to contain one row per frame, but got:", stack)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if FormatStack(fmt.Errorf(message), nil) != "" {
+			t.Fatal("Expected FormatStack of error not of type *internalError to be empty")
+		}
+	})
+}
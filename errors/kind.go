@@ -0,0 +1,157 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+)
+
+// Kind classifies errors, so they can be turned into correct responses by transport layers.
+type Kind int
+
+const (
+	KindUnknown Kind = iota
+	KindInvalidArgument
+	KindNotFound
+	KindConflict
+	KindUnauthenticated
+	KindPermissionDenied
+	KindFailedPrecondition
+	KindResourceExhausted
+	KindCanceled
+	KindDeadlineExceeded
+	KindUnimplemented
+	KindInternal
+	KindUnavailable
+	// KindTooLarge classifies requests or messages exceeding size limit.
+	KindTooLarge
+	// KindUnsupportedMediaType classifies requests in format that can't be handled.
+	KindUnsupportedMediaType
+)
+
+var kindNames = map[Kind]string{
+	KindUnknown:            "Unknown",
+	KindInvalidArgument:    "InvalidArgument",
+	KindNotFound:           "NotFound",
+	KindConflict:           "Conflict",
+	KindUnauthenticated:    "Unauthenticated",
+	KindPermissionDenied:   "PermissionDenied",
+	KindFailedPrecondition: "FailedPrecondition",
+	KindResourceExhausted:  "ResourceExhausted",
+	KindCanceled:           "Canceled",
+	KindDeadlineExceeded:   "DeadlineExceeded",
+	KindUnimplemented:      "Unimplemented",
+	KindInternal:           "Internal",
+	KindUnavailable:        "Unavailable",
+
+	KindTooLarge:             "TooLarge",
+	KindUnsupportedMediaType: "UnsupportedMediaType",
+}
+
+var kindHTTPStatuses = map[Kind]int{
+	KindUnknown:            http.StatusInternalServerError,
+	KindInvalidArgument:    http.StatusBadRequest,
+	KindNotFound:           http.StatusNotFound,
+	KindConflict:           http.StatusConflict,
+	KindUnauthenticated:    http.StatusUnauthorized,
+	KindPermissionDenied:   http.StatusForbidden,
+	KindFailedPrecondition: http.StatusPreconditionFailed,
+	KindResourceExhausted:  http.StatusTooManyRequests,
+	// 499 is not standard, but it is widely used for requests canceled by client.
+	KindCanceled:         499,
+	KindDeadlineExceeded: http.StatusGatewayTimeout,
+	KindUnimplemented:    http.StatusNotImplemented,
+	KindInternal:         http.StatusInternalServerError,
+	KindUnavailable:      http.StatusServiceUnavailable,
+
+	KindTooLarge:             http.StatusRequestEntityTooLarge,
+	KindUnsupportedMediaType: http.StatusUnsupportedMediaType,
+}
+
+// Codes from google.golang.org/grpc/codes, we don't want to depend on grpc just for them.
+var kindGRPCCodes = map[Kind]uint32{
+	KindUnknown:            2,
+	KindInvalidArgument:    3,
+	KindNotFound:           5,
+	KindConflict:           6,
+	KindUnauthenticated:    16,
+	KindPermissionDenied:   7,
+	KindFailedPrecondition: 9,
+	KindResourceExhausted:  8,
+	KindCanceled:           1,
+	KindDeadlineExceeded:   4,
+	KindUnimplemented:      12,
+	KindInternal:           13,
+	KindUnavailable:        14,
+
+	// grpc reports messages larger than its limit as ResourceExhausted.
+	KindTooLarge:             8,
+	KindUnsupportedMediaType: 3,
+}
+
+func (k Kind) String() string {
+	name, ok := kindNames[k]
+	if !ok {
+		return kindNames[KindUnknown]
+	}
+	return name
+}
+
+// HTTPStatus returns http status code corresponding to k.
+func (k Kind) HTTPStatus() int {
+	status, ok := kindHTTPStatuses[k]
+	if !ok {
+		return kindHTTPStatuses[KindUnknown]
+	}
+	return status
+}
+
+// GRPCCode returns grpc status code corresponding to k, it can be converted to codes.Code.
+func (k Kind) GRPCCode() uint32 {
+	code, ok := kindGRPCCodes[k]
+	if !ok {
+		return kindGRPCCodes[KindUnknown]
+	}
+	return code
+}
+
+// WithKind returns err classified as kind and internally calls WithStack.
+func WithKind(err error, kind Kind) error {
+	e := withStack(err)
+	e.kind = kind
+	return e
+}
+
+// KindOf returns kind of the outermost error in err's chain that was classified with WithKind.
+// Not classified context.Canceled and context.DeadlineExceeded errors are classified as KindCanceled and KindDeadlineExceeded.
+// For errors combined by Append it returns their kind if all of them are of the same kind, KindUnknown otherwise.
+func KindOf(err error) Kind {
+	if multiErr, ok := err.(*multiError); ok {
+		kind := KindOf(multiErr.errs[0])
+		for _, err := range multiErr.errs[1:] {
+			if KindOf(err) != kind {
+				return KindUnknown
+			}
+		}
+		return kind
+	}
+
+	for e := err; e != nil; e = Unwrap(e) {
+		if internalErr, ok := e.(*internalError); ok && internalErr.kind != KindUnknown {
+			return internalErr.kind
+		}
+	}
+
+	switch {
+	case Is(err, context.Canceled):
+		return KindCanceled
+	case Is(err, context.DeadlineExceeded):
+		return KindDeadlineExceeded
+	}
+
+	return KindUnknown
+}
+
+// HTTPStatus returns http status code corresponding to kind of err.
+func HTTPStatus(err error) int {
+	return KindOf(err).HTTPStatus()
+}
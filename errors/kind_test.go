@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestKindOf(t *testing.T) {
+	t.Run("unknown", func(t *testing.T) {
+		if KindOf(New(message)) != KindUnknown || KindOf(fmt.Errorf(message)) != KindUnknown {
+			t.Fatal("Expected kind of not classified error to be KindUnknown")
+		}
+	})
+
+	t.Run("withKind", func(t *testing.T) {
+		err := WithMessage(WithKind(New(message), KindNotFound), message)
+		if KindOf(err) != KindNotFound {
+			t.Fatal("Expected kind of error to be KindNotFound, but got:", KindOf(err))
+		}
+
+		err = WithKind(err, KindConflict)
+		if KindOf(err) != KindConflict {
+			t.Fatal("Expected kind of the outermost classified error to be returned, but got:", KindOf(err))
+		}
+	})
+
+	t.Run("context", func(t *testing.T) {
+		if KindOf(WithStack(context.DeadlineExceeded)) != KindDeadlineExceeded {
+			t.Fatal("Expected kind of context.DeadlineExceeded to be KindDeadlineExceeded")
+		}
+	})
+
+	t.Run("multiError", func(t *testing.T) {
+		err := Append(WithKind(New(message), KindNotFound), WithKind(New(message), KindNotFound))
+		if KindOf(err) != KindNotFound {
+			t.Fatal("Expected kind of errors of the same kind to be their kind, but got:", KindOf(err))
+		}
+
+		err = Append(err, New(message))
+		if KindOf(err) != KindUnknown {
+			t.Fatal("Expected kind of errors of different kinds to be KindUnknown, but got:", KindOf(err))
+		}
+	})
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := map[Kind]int{
+		KindUnknown:          http.StatusInternalServerError,
+		KindInvalidArgument:  http.StatusBadRequest,
+		KindNotFound:         http.StatusNotFound,
+		KindUnauthenticated:  http.StatusUnauthorized,
+		KindPermissionDenied: http.StatusForbidden,
+		KindUnavailable:      http.StatusServiceUnavailable,
+
+		KindTooLarge:             http.StatusRequestEntityTooLarge,
+		KindUnsupportedMediaType: http.StatusUnsupportedMediaType,
+	}
+
+	for kind, expectedStatus := range tests {
+		if status := HTTPStatus(WithKind(New(message), kind)); status != expectedStatus {
+			t.Fatalf("Expected http status of %v to be %v, but got %v", kind, expectedStatus, status)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// multiError combines multiple errors.
+type multiError struct {
+	errs []error
+}
+
+func (e *multiError) Error() string {
+	return e.error(true)
+}
+
+func (e *multiError) error(withStack bool) string {
+	rows := make([]string, 0, len(e.errs)+1)
+	rows = append(rows, fmt.Sprintf("%d errors occurred:", len(e.errs)))
+	for _, err := range e.errs {
+		var message string
+		if internalErr, ok := err.(*internalError); ok {
+			message = internalErr.error(withStack)
+		} else {
+			message = err.Error()
+		}
+		rows = append(rows, "* "+message)
+	}
+
+	return strings.Join(rows, "\n")
+}
+
+// Is reports whether any of combined errors matches target.
+func (e *multiError) Is(target error) bool {
+	for _, err := range e.errs {
+		if Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of combined errors that matches target.
+func (e *multiError) As(target interface{}) bool {
+	for _, err := range e.errs {
+		if As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Append combines err and errs into one error, nil errors are skipped.
+// It returns nil if all errors are nil and the error itself if only one is non nil.
+// Errors already combined by Append are flattened, so Errors returns all of them.
+func Append(err error, errs ...error) error {
+	combined := make([]error, 0, len(errs)+1)
+	for _, e := range append([]error{err}, errs...) {
+		if e == nil {
+			continue
+		}
+
+		if multiErr, ok := e.(*multiError); ok {
+			combined = append(combined, multiErr.errs...)
+			continue
+		}
+		combined = append(combined, e)
+	}
+
+	switch len(combined) {
+	case 0:
+		return nil
+	case 1:
+		return combined[0]
+	default:
+		return &multiError{errs: combined}
+	}
+}
+
+// Errors returns errors combined by Append, if err is not combined it returns slice containing only err.
+func Errors(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	multiErr, ok := err.(*multiError)
+	if !ok {
+		return []error{err}
+	}
+
+	errs := make([]error, len(multiErr.errs))
+	copy(errs, multiErr.errs)
+	return errs
+}
+
+// Collector combines errors appended concurrently from multiple goroutines,
+// zero value is ready to use.
+type Collector struct {
+	mu  sync.Mutex
+	err error
+}
+
+// Append appends err to collected errors, nil err is skipped.
+func (c *Collector) Append(err error) {
+	if err == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = Append(c.err, err)
+}
+
+// Err returns collected errors combined by Append.
+func (c *Collector) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
@@ -0,0 +1,80 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestAppend(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		if Append(nil, nil, nil) != nil {
+			t.Fatal("Expected Append of nil errors to return nil")
+		}
+	})
+
+	t.Run("single", func(t *testing.T) {
+		err := fmt.Errorf(message)
+		if Append(nil, err, nil) != err {
+			t.Fatal("Expected Append of single non nil error to return that error")
+		}
+	})
+
+	t.Run("multiple", func(t *testing.T) {
+		err1 := New(message)
+		err2 := fmt.Errorf(message)
+		err3 := New(message)
+
+		err := Append(Append(err1, err2), nil, err3)
+		errs := Errors(err)
+		if len(errs) != 3 || errs[0] != err1 || errs[1] != err2 || errs[2] != err3 {
+			t.Fatal("Expected Errors to return flattened appended errors in order, but got:", errs)
+		}
+
+		for _, e := range []error{err1, err2, err3} {
+			if !Is(err, e) {
+				t.Fatal("Expected Is to match every appended error")
+			}
+		}
+
+		if !strings.HasPrefix(err.Error(), "3 errors occurred:") {
+			t.Fatal("Expected error message to start with number of errors, but got:", err.Error())
+		}
+
+		if GetErrorStack(err) != err1.(*internalError).stack.String()+"\n"+err3.(*internalError).stack.String() {
+			t.Fatal("Expected GetErrorStack to return stacks of all appended errors")
+		}
+	})
+
+	t.Run("as", func(t *testing.T) {
+		err := Append(fmt.Errorf(message), New(message))
+
+		var internalErr *internalError
+		if !As(err, &internalErr) {
+			t.Fatal("Expected As to find appended *internalError")
+		}
+	})
+}
+
+func TestCollector(t *testing.T) {
+	c := Collector{}
+	if c.Err() != nil {
+		t.Fatal("Expected zero value Collector to have no error")
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Append(New(message))
+			c.Append(nil)
+		}()
+	}
+	wg.Wait()
+
+	if len(Errors(c.Err())) != 10 {
+		t.Fatal("Expected Collector to collect all appended errors, but got:", len(Errors(c.Err())))
+	}
+}
@@ -0,0 +1,36 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// FromPanic creates error from value returned by recover, stack of returned error starts where panic occurred,
+// not in the deferred function, and it is classified as KindInternal.
+// If recovered is an error it is wrapped, so Is and As work with it.
+// FromPanic must be called directly by the deferred function that called recover.
+func FromPanic(recovered interface{}) error {
+	st := callers(0)
+	for i, pc := range st {
+		fn := runtime.FuncForPC(pc)
+		if fn != nil && fn.Name() == "runtime.gopanic" {
+			st = st[i+1:]
+			break
+		}
+	}
+
+	if err, ok := recovered.(error); ok {
+		return &internalError{
+			message:    "panic",
+			wrappedErr: err,
+			stack:      st,
+			kind:       KindInternal,
+		}
+	}
+
+	return &internalError{
+		message: fmt.Sprintf("panic: %v", recovered),
+		stack:   st,
+		kind:    KindInternal,
+	}
+}
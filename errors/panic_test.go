@@ -0,0 +1,46 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func panicking(v interface{}) {
+	panic(v)
+}
+
+func recoverFromPanic(v interface{}) (err error) {
+	defer func() {
+		err = FromPanic(recover())
+	}()
+
+	panicking(v)
+	return nil
+}
+
+func TestFromPanic(t *testing.T) {
+	t.Run("stack", func(t *testing.T) {
+		err := recoverFromPanic(message)
+
+		frames := Frames(err)
+		if len(frames) == 0 || !strings.HasSuffix(frames[0].Func, "errors.panicking") {
+			t.Fatal("Expected first frame to be the function that panicked, but got:", frames)
+		}
+	})
+
+	t.Run("value", func(t *testing.T) {
+		err := recoverFromPanic(message)
+		if GetErrorNoStack(err) != "panic: "+message {
+			t.Fatal("Expected message to contain panic value, but got:", GetErrorNoStack(err))
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		panicErr := fmt.Errorf(message)
+		err := recoverFromPanic(panicErr)
+		if !Is(err, panicErr) {
+			t.Fatal("Expected error to wrap panic error")
+		}
+	})
+}
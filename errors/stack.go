@@ -2,49 +2,85 @@ package errors
 
 import (
 	"runtime"
-	"strings"
 )
 
 // stack represents a stack of program counters.
 type stack []uintptr
 
 func callers(skip int) stack {
-	const depth = 32
-	var pcs [depth]uintptr
-	n := runtime.Callers(3+skip, pcs[:])
+	pcs := make([]uintptr, getStackConfig().Depth)
+	n := runtime.Callers(3+skip, pcs)
 	var st stack = pcs[0:n]
 	return st
 }
 
-type frame struct {
-	Func string
-	Path string
-	Line int
-}
+// frames returns frames of s, frames from skipped packages are not included.
+func (s stack) frames() []Frame {
+	config := getStackConfig()
 
-func newFrame(pc uintptr) *frame {
-	fn := runtime.FuncForPC(pc)
-	path, line := fn.FileLine(pc - 1)
-	return &frame{
-		Func: fn.Name(),
-		Line: line,
-		Path: path,
+	frames := make([]Frame, 0, len(s))
+	if len(s) == 0 {
+		return frames
 	}
+
+	// CallersFrames also resolves frames of inlined functions.
+	callersFrames := runtime.CallersFrames(s)
+	for {
+		f, more := callersFrames.Next()
+
+		// Don't include runtime and other skipped packages.
+		if !config.isSkippedFunc(f.Function) {
+			frames = append(frames, Frame{
+				Func: f.Function,
+				File: f.File,
+				Line: f.Line,
+			})
+		}
+
+		if !more {
+			break
+		}
+	}
+	return frames
 }
 
-func isValidPC(pc uintptr) bool {
-	fn := runtime.FuncForPC(pc)
-	path, _ := fn.FileLine(pc - 1)
+// Frame is a single frame of error's stack.
+type Frame struct {
+	Func string `json:"func"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
 
-	return isValidFramePath(path)
+func isValidPC(pc uintptr) bool {
+	return isValidFunc(runtime.FuncForPC(pc))
 }
 
-func isValidFrame(f *frame) bool {
-	return isValidFramePath(f.Path)
+// trimmedFrames returns frames of s with file paths trimmed by TrimPrefixes of StackConfig.
+func (s stack) trimmedFrames() []Frame {
+	config := getStackConfig()
+
+	frames := s.frames()
+	for i := range frames {
+		frames[i].File = config.trimPath(frames[i].File)
+	}
+	return frames
 }
 
-func isValidFramePath(path string) bool {
-	
-	// we don't want to include runtime in stack traces
-	return !strings.Contains(path, "libexec/src/runtime")
+// Frames returns frames of err's stack, for errors combined by Append it returns frames of all of them
+// note that err should be possessed from New, WithMessage or Append func, otherwise nil is returned.
+func Frames(err error) []Frame {
+	if multiErr, ok := err.(*multiError); ok {
+		var frames []Frame
+		for _, err := range multiErr.errs {
+			frames = append(frames, Frames(err)...)
+		}
+		return frames
+	}
+
+	e, ok := err.(*internalError)
+	if !ok || e.stack == nil {
+		return nil
+	}
+
+	return e.stack.trimmedFrames()
 }
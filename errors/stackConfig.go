@@ -0,0 +1,100 @@
+package errors
+
+import (
+	"go/build"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// StackConfig configures how stacks of errors are recorded and displayed.
+type StackConfig struct {
+	// Depth is the maximum number of frames recorded, values less than 1 are replaced with default depth.
+	Depth int
+
+	// SkipPackages are packages whose frames are not displayed, subpackages must be listed separately.
+	SkipPackages []string
+
+	// TrimPrefixes are prefixes removed from displayed file paths, the first matching one is removed.
+	TrimPrefixes []string
+}
+
+// DefaultStackConfig returns config used when SetStackConfig was not called,
+// it records 32 frames, skips runtime, testing and net/http frames, and trims GOROOT and GOPATH prefixes.
+func DefaultStackConfig() *StackConfig {
+	trimPrefixes := []string{filepath.ToSlash(filepath.Join(build.Default.GOROOT, "src")) + "/"}
+	for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+		gopath = filepath.ToSlash(gopath)
+		trimPrefixes = append(trimPrefixes, gopath+"/pkg/mod/", gopath+"/src/")
+	}
+
+	return &StackConfig{
+		Depth:        defaultStackDepth,
+		SkipPackages: []string{"runtime", "testing", "net/http"},
+		TrimPrefixes: trimPrefixes,
+	}
+}
+
+const defaultStackDepth = 32
+
+var stackConfig atomic.Value
+
+func init() {
+	stackConfig.Store(DefaultStackConfig())
+}
+
+// SetStackConfig sets config used by all errors created after the call, it is safe for concurrent use.
+// Nil config resets to DefaultStackConfig.
+func SetStackConfig(config *StackConfig) {
+	if config == nil {
+		config = DefaultStackConfig()
+	}
+
+	c := *config
+	if c.Depth < 1 {
+		c.Depth = defaultStackDepth
+	}
+	c.SkipPackages = append([]string(nil), config.SkipPackages...)
+	c.TrimPrefixes = append([]string(nil), config.TrimPrefixes...)
+	stackConfig.Store(&c)
+}
+
+func getStackConfig() *StackConfig {
+	return stackConfig.Load().(*StackConfig)
+}
+
+// isSkippedFunc reports whether function with name fn is from one of skipped packages.
+func (c *StackConfig) isSkippedFunc(fn string) bool {
+	pkg := funcPackage(fn)
+	for _, skip := range c.SkipPackages {
+		if pkg == skip {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *StackConfig) trimPath(path string) string {
+	for _, prefix := range c.TrimPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return strings.TrimPrefix(path, prefix)
+		}
+	}
+	return path
+}
+
+// funcPackage returns import path of package from fully qualified function name,
+// e.g. net/http for net/http.(*conn).serve.
+func funcPackage(fn string) string {
+	lastSlash := strings.LastIndexByte(fn, '/')
+	dot := strings.IndexByte(fn[lastSlash+1:], '.')
+	if dot == -1 {
+		return fn
+	}
+	return fn[:lastSlash+1+dot]
+}
+
+func isValidFunc(fn *runtime.Func) bool {
+	return fn != nil && !getStackConfig().isSkippedFunc(fn.Name())
+}
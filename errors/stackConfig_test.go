@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFuncPackage(t *testing.T) {
+	tests := map[string]string{
+		"runtime.goexit":                          "runtime",
+		"net/http.(*conn).serve":                  "net/http",
+		"github.com/corioders/gokit/errors.New":   "github.com/corioders/gokit/errors",
+		"github.com/corioders/gokit/errors.func1": "github.com/corioders/gokit/errors",
+		"main.main": "main",
+	}
+
+	for fn, expectedPkg := range tests {
+		if pkg := funcPackage(fn); pkg != expectedPkg {
+			t.Fatalf("Expected package of %v to be %v, but got %v", fn, expectedPkg, pkg)
+		}
+	}
+}
+
+func TestSetStackConfig(t *testing.T) {
+	defer SetStackConfig(DefaultStackConfig())
+
+	t.Run("skip packages", func(t *testing.T) {
+		SetStackConfig(DefaultStackConfig())
+
+		for _, frame := range Frames(New(message)) {
+			if strings.HasPrefix(frame.Func, "runtime.") || strings.HasPrefix(frame.Func, "testing.") {
+				t.Fatal("Expected frames not to contain skipped packages, but got:", frame.Func)
+			}
+		}
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		config := DefaultStackConfig()
+		config.Depth = 1
+		config.SkipPackages = nil
+		SetStackConfig(config)
+
+		if len(Frames(New(message))) != 1 {
+			t.Fatal("Expected number of frames to be limited by Depth")
+		}
+	})
+
+	t.Run("invalid depth", func(t *testing.T) {
+		for _, depth := range []int{0, -1} {
+			config := DefaultStackConfig()
+			config.Depth = depth
+			SetStackConfig(config)
+
+			if len(Frames(New(message))) == 0 {
+				t.Fatal("Expected invalid depth to be replaced with default depth, depth:", depth)
+			}
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		SetStackConfig(nil)
+
+		if getStackConfig().Depth != defaultStackDepth {
+			t.Fatal("Expected nil config to be replaced with default config, but got depth:", getStackConfig().Depth)
+		}
+	})
+
+	t.Run("exact packages", func(t *testing.T) {
+		config := DefaultStackConfig()
+		config.SkipPackages = []string{"net/http"}
+		SetStackConfig(config)
+
+		if !getStackConfig().isSkippedFunc("net/http.(*conn).serve") {
+			t.Fatal("Expected net/http frames to be skipped")
+		}
+		if getStackConfig().isSkippedFunc("net/http/httptest.(*Server).Start") {
+			t.Fatal("Expected net/http/httptest frames not to be skipped")
+		}
+	})
+
+	t.Run("trim prefixes", func(t *testing.T) {
+		file := Frames(New(message))[0].File
+
+		config := DefaultStackConfig()
+		config.TrimPrefixes = []string{strings.TrimSuffix(file, "stackConfig_test.go")}
+		SetStackConfig(config)
+
+		frames := Frames(New(message))
+		if frames[0].File != "stackConfig_test.go" {
+			t.Fatal("Expected file path to be trimmed, but got:", frames[0].File)
+		}
+	})
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/corioders/gokit/constant"
+
 	"github.com/logrusorgru/aurora"
 )
 
@@ -15,26 +17,70 @@ const linesAfter = 3
 // linesBefore is number of source lines before traced line to display.
 const linesBefore = 3
 
+// StackFormatOptions configures stack rendering of FormatStack.
+type StackFormatOptions struct {
+	// Color enables ANSI colour codes.
+	Color bool
+
+	// Source enables displaying source lines around traced lines, source is read from disk.
+	Source bool
+}
+
+// defaultSource is true when source lines should be displayed by default,
+// in production we don't want to leak source.
+const defaultSource = !constant.IsProduction
+
 func (s stack) String() string {
-	expectedRows := (linesBefore + linesAfter + 3) * len(s)
-	rows := make([]string, 0, expectedRows)
-	for _, pc := range s {
-		frame := newFrame(pc)
+	return s.format(&StackFormatOptions{Color: true, Source: defaultSource})
+}
 
-		// Don't display runtime.
-		if !isValidFrame(frame) {
-			break
-		}
+func (s stack) format(options *StackFormatOptions) string {
+	au := aurora.NewAurora(options.Color)
+	config := getStackConfig()
+
+	frames := s.frames()
+	expectedRows := (linesBefore + linesAfter + 3) * len(frames)
+	rows := make([]string, 0, expectedRows)
+	for i := range frames {
+		frame := &frames[i]
 
-		message := aurora.Sprintf(aurora.Bold("%s:%d"), frame.Path, frame.Line)
+		message := au.Sprintf(au.Bold("%s:%d"), config.trimPath(frame.File), frame.Line)
 		rows = append(rows, message)
-		rows = sourceRows(rows, frame)
+		if options.Source {
+			rows = sourceRows(au, rows, frame)
+		}
 	}
 	return strings.Join(rows, "\n")
 }
 
-func sourceRows(rows []string, frame *frame) []string {
-	lines := readLines(frame.Path)
+// FormatStack renders stack of err, for errors combined by Append it renders stacks of all of them.
+// If options are nil, stack is rendered without colours and with source lines only outside production.
+// Note that err should be possessed from New, WithMessage or Append func, otherwise empty string is returned.
+func FormatStack(err error, options *StackFormatOptions) string {
+	if options == nil {
+		options = &StackFormatOptions{Source: defaultSource}
+	}
+
+	if multiErr, ok := err.(*multiError); ok {
+		stacks := make([]string, 0, len(multiErr.errs))
+		for _, err := range multiErr.errs {
+			if stack := FormatStack(err, options); stack != "" {
+				stacks = append(stacks, stack)
+			}
+		}
+		return strings.Join(stacks, "\n")
+	}
+
+	e, ok := err.(*internalError)
+	if !ok || e.stack == nil {
+		return ""
+	}
+
+	return e.stack.format(options)
+}
+
+func sourceRows(au aurora.Aurora, rows []string, frame *Frame) []string {
+	lines := readLines(frame.File)
 	if lines == nil {
 		return rows
 	}
@@ -52,9 +98,9 @@ func sourceRows(rows []string, frame *frame) []string {
 		line := lines[i]
 		var message string
 		if i == frame.Line-1 {
-			message = aurora.Red(fmt.Sprintf("%d\t%s", i+1, line)).String()
+			message = au.Red(fmt.Sprintf("%d\t%s", i+1, line)).String()
 		} else {
-			message = aurora.Sprintf("%d\t%s", aurora.Blue(i+1), line)
+			message = au.Sprintf("%d\t%s", au.Blue(i+1), line)
 		}
 		rows = append(rows, message)
 	}
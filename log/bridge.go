@@ -0,0 +1,81 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	stdlog "log"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+)
+
+// NewStdLogger returns standard library logger that logs messages with l at level,
+// it can be used for example as http.Server.ErrorLog.
+func NewStdLogger(l Logger, level Level) *stdlog.Logger {
+	return stdlog.New(&stdWriter{logger: Structured(l), level: level}, "", 0)
+}
+
+type stdWriter struct {
+	logger StructuredLogger
+	level  Level
+}
+
+func (w *stdWriter) Write(p []byte) (int, error) {
+	w.logger.Log(w.level, strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
+// NewOtelErrorHandler returns otel.ErrorHandler that logs errors with l,
+// it can be set with otel.SetErrorHandler.
+func NewOtelErrorHandler(l Logger) otel.ErrorHandler {
+	return &otelErrorHandler{logger: l}
+}
+
+type otelErrorHandler struct {
+	logger Logger
+}
+
+func (h *otelErrorHandler) Handle(err error) {
+	h.logger.Error(err)
+}
+
+// Handler handles entries logged by logger created with FromHandler,
+// level and prefixes of the logger are available in the entry.
+type Handler interface {
+	Handle(entry *Entry) error
+}
+
+// HandlerFunc is function used as Handler.
+type HandlerFunc func(entry *Entry) error
+
+func (f HandlerFunc) Handle(entry *Entry) error {
+	return f(entry)
+}
+
+// FromHandler returns logger that passes logged entries to h instead of encoding them, WithEncoder option is ignored.
+func FromHandler(h Handler, prefix string, opts ...Option) StructuredLogger {
+	return New(io.Discard, prefix, append(opts, WithEncoder(&handlerEncoder{handler: h}))...)
+}
+
+type handlerEncoder struct {
+	handler Handler
+}
+
+func (e *handlerEncoder) Encode(buf *bytes.Buffer, entry *Entry) error {
+	return e.handler.Handle(entry)
+}
+
+// FromStd returns logger that writes encoded entries with l, so they are written with l's prefix and flags.
+func FromStd(l *stdlog.Logger, prefix string, opts ...Option) StructuredLogger {
+	return New(&stdOutput{logger: l}, prefix, opts...)
+}
+
+type stdOutput struct {
+	logger *stdlog.Logger
+}
+
+func (o *stdOutput) Write(p []byte) (int, error) {
+	// Standard library logger adds newline itself.
+	o.logger.Print(strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
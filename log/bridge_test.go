@@ -0,0 +1,58 @@
+package log
+
+import (
+	"bytes"
+	stdlog "log"
+	"strings"
+	"testing"
+
+	"github.com/corioders/gokit/errors"
+)
+
+func TestBridge(t *testing.T) {
+	t.Run("std logger", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		stdLogger := NewStdLogger(New(buf, "server"), LevelWarn)
+
+		stdLogger.Println("message")
+		if stripColors(buf.String()) != "WARN: server: message\n" {
+			t.Fatal("Expected message to be logged with level and prefix, but got:", stripColors(buf.String()))
+		}
+	})
+
+	t.Run("otel error handler", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		NewOtelErrorHandler(New(buf, "otel")).Handle(errors.New("Export failed"))
+
+		if !strings.HasPrefix(stripColors(buf.String()), "ERROR: otel: Export failed\n") {
+			t.Fatal("Expected error to be logged, but got:", stripColors(buf.String()))
+		}
+	})
+
+	t.Run("from handler", func(t *testing.T) {
+		var entries []*Entry
+		logger := FromHandler(HandlerFunc(func(entry *Entry) error {
+			entries = append(entries, entry)
+			return nil
+		}), "parent", WithLevel(LevelInfo))
+
+		logger.Debug("debug")
+		logger.Child("child").Error("message")
+		if len(entries) != 1 {
+			t.Fatal("Expected one entry to be handled, but got:", len(entries))
+		}
+		if entries[0].Level != LevelError || len(entries[0].Prefixes) != 2 || entries[0].Message != "message" {
+			t.Fatal("Expected entry to have level and prefixes, but got:", entries[0])
+		}
+	})
+
+	t.Run("from std", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := FromStd(stdlog.New(buf, "std ", 0), "prefix", WithEncoder(NewLogfmtEncoder()))
+
+		logger.Info("message")
+		if !bytes.HasPrefix(buf.Bytes(), []byte("std ")) || !bytes.Contains(buf.Bytes(), []byte("level=info prefix=prefix msg=message")) {
+			t.Fatal("Expected message to be written with std logger, but got:", buf.String())
+		}
+	})
+}
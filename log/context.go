@@ -0,0 +1,105 @@
+package log
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/corioders/gokit/constant"
+	"github.com/corioders/gokit/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ctxKey int
+
+const (
+	ctxKeyLogger ctxKey = iota
+)
+
+// defaultLogger is returned by FromContext when there is no logger in context.
+var defaultLogger = New(os.Stderr, "")
+
+// NewContext returns copy of ctx that carries l.
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, ctxKeyLogger, l)
+}
+
+// FromContext returns logger carried by ctx bound to ctx with WithContext,
+// if ctx doesn't carry logger, logger writing to os.Stderr is used.
+func FromContext(ctx context.Context) StructuredLogger {
+	l, ok := ctx.Value(ctxKeyLogger).(Logger)
+	if !ok {
+		l = defaultLogger
+	}
+
+	return WithContext(ctx, l)
+}
+
+// WithContext returns l with trace_id and span_id fields of span active in ctx,
+// if there is no active span l is returned as StructuredLogger.
+// When l was created with WithSpanEvents option, logged messages are also recorded as events of the span.
+func WithContext(ctx context.Context, l Logger) StructuredLogger {
+	span := trace.SpanFromContext(ctx)
+	spanContext := span.SpanContext()
+	if !spanContext.IsValid() {
+		return Structured(l)
+	}
+
+	fields := []Field{
+		String("trace_id", spanContext.TraceID().String()),
+		String("span_id", spanContext.SpanID().String()),
+	}
+
+	gl, ok := l.(*logger)
+	if !ok {
+		return Structured(l).With(fields...)
+	}
+
+	child := gl.clone()
+	child.fields = append(child.fields, fields...)
+	if child.spanEvents {
+		child.span = span
+	}
+	return child
+}
+
+// WithSpanEvents enables recording messages logged by loggers bound to context with WithContext
+// as events of span active in the context.
+func WithSpanEvents() Option {
+	return func(o *options) {
+		o.spanEvents = true
+	}
+}
+
+// addSpanEvent records entry as event of span.
+func addSpanEvent(span trace.Span, entry *Entry) {
+	if !span.IsRecording() {
+		return
+	}
+
+	attributes := make([]attribute.KeyValue, 0, len(entry.Fields)+len(entry.Errors)+2)
+	attributes = append(attributes, attribute.String("level", strings.ToLower(entry.Level.String())))
+	if len(entry.Prefixes) != 0 {
+		attributes = append(attributes, attribute.String("prefix", strings.Join(entry.Prefixes, constant.Delimer)))
+	}
+
+	for _, field := range entry.Fields {
+		// Trace and span ids are already known to the span.
+		if field.Key == "trace_id" || field.Key == "span_id" {
+			continue
+		}
+
+		if err, ok := field.Value.(error); ok {
+			attributes = append(attributes, attribute.String(field.Key, errors.GetErrorNoStack(err)))
+			continue
+		}
+		attributes = append(attributes, attribute.Any(field.Key, field.Value))
+	}
+
+	for _, err := range entry.Errors {
+		attributes = append(attributes, attribute.String("error", errors.GetErrorNoStack(err)))
+	}
+
+	span.AddEvent(entry.Message, trace.WithAttributes(attributes...))
+}
@@ -0,0 +1,61 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestContext(t *testing.T) {
+	t.Run("no span", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		ctx := NewContext(context.Background(), New(buf, "prefix"))
+
+		FromContext(ctx).Info("message")
+		if stripColors(buf.String()) != "INFO: prefix: message\n" {
+			t.Fatal("Expected logger from context to be used without trace fields, but got:", stripColors(buf.String()))
+		}
+	})
+
+	t.Run("span", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "span")
+		defer span.End()
+
+		WithContext(ctx, New(buf, "")).Info("message")
+		spanContext := span.SpanContext()
+		expected := "trace_id=" + spanContext.TraceID().String() + " span_id=" + spanContext.SpanID().String()
+		if !strings.Contains(stripColors(buf.String()), expected) {
+			t.Fatal("Expected trace_id and span_id fields to be logged, but got:", stripColors(buf.String()))
+		}
+
+		if len(span.(sdktrace.ReadOnlySpan).Events()) != 0 {
+			t.Fatal("Expected no span events without WithSpanEvents option")
+		}
+	})
+
+	t.Run("span events", func(t *testing.T) {
+		ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "span")
+		defer span.End()
+
+		WithContext(ctx, New(&bytes.Buffer{}, "", WithSpanEvents()).Child("child")).Warn("message", Int("count", 2))
+		events := span.(sdktrace.ReadOnlySpan).Events()
+		if len(events) != 1 {
+			t.Fatal("Expected one span event, but got:", len(events))
+		}
+		if events[0].Name != "message" {
+			t.Fatal("Expected span event to be named after message, but got:", events[0].Name)
+		}
+
+		attributes := map[string]string{}
+		for _, a := range events[0].Attributes {
+			attributes[string(a.Key)] = a.Value.Emit()
+		}
+		if attributes["level"] != "warn" || attributes["prefix"] != "child" || attributes["count"] != "2" {
+			t.Fatal("Expected span event to have level, prefix and fields attributes, but got:", attributes)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/corioders/gokit/constant"
+	"github.com/corioders/gokit/errors"
+
+	"github.com/logrusorgru/aurora"
+)
+
+// Encoder writes entry to buf, one entry per line.
+type Encoder interface {
+	Encode(buf *bytes.Buffer, entry *Entry) error
+}
+
+// fieldKey returns key under which field with key is written by encoder writing reserved keys itself,
+// colliding keys are prefixed with "fields.", e.g. "fields.level", so they don't override them.
+func fieldKey(key string, reserved map[string]bool) string {
+	if reserved[key] {
+		return "fields." + key
+	}
+	return key
+}
+
+type consoleEncoder struct{}
+
+var statuses = map[Level]string{
+	LevelDebug: aurora.Magenta("DEBUG" + constant.Delimer).String(),
+	LevelInfo:  aurora.Blue("INFO" + constant.Delimer).String(),
+	LevelWarn:  aurora.Yellow("WARN" + constant.Delimer).String(),
+	LevelError: aurora.Red("ERROR" + constant.Delimer).String(),
+	LevelFatal: aurora.Red("FATAL" + constant.Delimer).String(),
+}
+
+// NewConsoleEncoder creates encoder that writes human readable, coloured entries,
+// fields are written after message and stacks of errors are written in the following lines.
+func NewConsoleEncoder() Encoder {
+	return consoleEncoder{}
+}
+
+func (consoleEncoder) Encode(buf *bytes.Buffer, entry *Entry) error {
+	buf.WriteString(statuses[entry.Level])
+	if len(entry.Prefixes) != 0 {
+		buf.WriteString(aurora.Yellow(strings.Join(entry.Prefixes, constant.Delimer) + constant.Delimer).String())
+	}
+
+	stacks := make([]string, 0)
+	for _, err := range entry.Errors {
+		stacks = appendStack(stacks, err)
+	}
+
+	message := entry.Message
+	firstLineEnd := strings.IndexByte(message, '\n')
+	if firstLineEnd == -1 {
+		firstLineEnd = len(message)
+	}
+	buf.WriteString(message[:firstLineEnd])
+
+	for _, field := range entry.Fields {
+		value := fmt.Sprint(field.Value)
+		// Stack of error is written with stacks of entry.Errors, they contain errors of fields.
+		if err, ok := field.Value.(error); ok {
+			value = errors.GetErrorNoStack(err)
+		}
+
+		buf.WriteString(" " + aurora.Cyan(field.Key+"=").String() + value)
+	}
+
+	if entry.Caller != nil {
+		buf.WriteString(" " + aurora.Faint(entry.Caller.String()).String())
+	}
+
+	buf.WriteString(message[firstLineEnd:])
+	for _, stack := range stacks {
+		buf.WriteString("\n" + stack)
+	}
+	buf.WriteString("\n")
+	return nil
+}
+
+func appendStack(stacks []string, err error) []string {
+	if errors.Frames(err) == nil {
+		return stacks
+	}
+
+	stack := errors.GetErrorStack(err)
+	if stack == "" {
+		return stacks
+	}
+	return append(stacks, stack)
+}
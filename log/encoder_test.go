@@ -0,0 +1,89 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/corioders/gokit/errors"
+)
+
+func TestJSONEncoder(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(buf, "parent", WithEncoder(NewJSONEncoder()), WithCaller()).Child("child")
+
+	err := errors.With(errors.New("test error"), "id", 1)
+	logger.Error("message", err, String("key", "value"), String("level", "field"))
+
+	line := map[string]interface{}{}
+	unmarshalErr := json.Unmarshal(buf.Bytes(), &line)
+	if unmarshalErr != nil {
+		t.Fatal("Expected entry to be valid json, error:", unmarshalErr, buf.String())
+	}
+
+	expected := map[string]interface{}{
+		"level":  "error",
+		"prefix": "parent: child",
+		"msg":    "message test error",
+		"key":    "value",
+		"id":     float64(1),
+
+		"fields.level": "field",
+	}
+	for key, value := range expected {
+		if line[key] != value {
+			t.Fatalf("Expected %v to be %v, but got %v", key, value, line[key])
+		}
+	}
+
+	if _, ok := line["time"]; !ok {
+		t.Fatal("Expected entry to contain time")
+	}
+	if caller, ok := line["caller"].(string); !ok || !strings.Contains(caller, "encoder_test.go") {
+		t.Fatal("Expected caller to be location of logging call, but got:", line["caller"])
+	}
+
+	errs, ok := line["errors"].([]interface{})
+	if !ok || len(errs) != 1 {
+		t.Fatal("Expected entry to contain logged error, but got:", line["errors"])
+	}
+	if stack, ok := errs[0].(map[string]interface{})["stack"].([]interface{}); !ok || len(stack) == 0 {
+		t.Fatal("Expected logged error to contain stack, but got:", errs[0])
+	}
+}
+
+func TestLogfmtEncoder(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(buf, "prefix", WithEncoder(NewLogfmtEncoder()))
+
+	logger.Info("message with spaces", String("key", "value"), Int("number", 1))
+
+	line := buf.String()
+	if !strings.HasSuffix(line, "\n") || strings.Count(line, "\n") != 1 {
+		t.Fatal("Expected entry to be single line, but got:", line)
+	}
+
+	for _, expected := range []string{" level=info ", " prefix=prefix ", ` msg="message with spaces" `, " key=value ", " number=1\n"} {
+		if !strings.Contains(line, expected) {
+			t.Fatalf("Expected entry to contain %q, but got: %v", expected, line)
+		}
+	}
+	if strings.Contains(line, "\x1b[") {
+		t.Fatal("Expected entry not to contain ANSI escape codes")
+	}
+
+	buf.Reset()
+	logger.Error("message", String("level", "field"), Err(errors.New("field error")), errors.New("test error"))
+	line = buf.String()
+	for _, key := range []string{"level", "error", "error_stack"} {
+		if strings.Count(line, " "+key+"=") != 1 {
+			t.Fatalf("Expected entry to contain key %v once, but got: %v", key, line)
+		}
+	}
+	for _, expected := range []string{" level=error ", " fields.level=field ", ` error="field error; test error" `} {
+		if !strings.Contains(line, expected) {
+			t.Fatalf("Expected entry to contain %q, but got: %v", expected, line)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package log
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/corioders/gokit/errors"
+)
+
+// Entry is a single logged message, it is passed to Encoder.
+type Entry struct {
+	Time     time.Time
+	Level    Level
+	Prefixes []string
+	// Message is formatted from arguments that are not fields, errors are formatted without stacks.
+	Message string
+	// Errors are errors passed as arguments, including errors that are values of fields.
+	Errors []error
+	Fields []Field
+	// Caller is nil unless logger was created with WithCaller option.
+	Caller *Caller
+}
+
+// Caller is location in source from which message was logged.
+type Caller struct {
+	Func string
+	File string
+	Line int
+}
+
+func (c *Caller) String() string {
+	return fmt.Sprintf("%s:%d", c.File, c.Line)
+}
+
+// newCaller returns caller skip frames above caller of newCaller.
+func newCaller(skip int) *Caller {
+	pc, file, line, ok := runtime.Caller(skip + 2)
+	if !ok {
+		return nil
+	}
+
+	caller := &Caller{File: file, Line: line}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		caller.Func = fn.Name()
+	}
+	return caller
+}
+
+// newEntry creates entry from arguments passed to logging methods,
+// fields are separated from other arguments and fields attached to errors are added to them.
+func newEntry(level Level, prefixes []string, fields []Field, a []interface{}) *Entry {
+	entry := &Entry{
+		Time:     time.Now(),
+		Level:    level,
+		Prefixes: prefixes,
+		Fields:   fields,
+	}
+
+	for _, field := range fields {
+		if err, ok := field.Value.(error); ok && err != nil {
+			entry.addError(err)
+		}
+	}
+
+	args := make([]interface{}, 0, len(a))
+	for _, v := range a {
+		switch v := v.(type) {
+		case Field:
+			entry.Fields = append(entry.Fields, v)
+			// Errors passed as fields, e.g. with Err, are recorded the same as errors passed directly.
+			if err, ok := v.Value.(error); ok && err != nil {
+				entry.addError(err)
+			}
+		case error:
+			args = append(args, errors.GetErrorNoStack(v))
+			entry.addError(v)
+		default:
+			args = append(args, v)
+		}
+	}
+
+	// Sprintln always adds spaces between operands.
+	entry.Message = strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+	return entry
+}
+
+// addError adds err and fields attached to it to e.
+func (e *Entry) addError(err error) {
+	e.Errors = append(e.Errors, err)
+	for _, field := range errors.Fields(err) {
+		e.Fields = append(e.Fields, Field{Key: field.Key, Value: field.Value})
+	}
+}
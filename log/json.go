@@ -0,0 +1,98 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/corioders/gokit/constant"
+	"github.com/corioders/gokit/errors"
+)
+
+type jsonEncoder struct{}
+
+// jsonReservedKeys are keys written by jsonEncoder, fields must not override them.
+var jsonReservedKeys = map[string]bool{
+	"time":   true,
+	"level":  true,
+	"prefix": true,
+	"msg":    true,
+	"caller": true,
+	"errors": true,
+}
+
+type errorJSON struct {
+	Message string         `json:"message"`
+	Stack   []errors.Frame `json:"stack,omitempty"`
+}
+
+// NewJSONEncoder creates encoder that writes entries as json objects, one per line.
+// Fields are written as object's keys, errors are written under "errors" key with their stacks.
+// Fields with keys used by encoder itself, e.g. "level", are written with "fields." prefix, e.g. "fields.level".
+func NewJSONEncoder() Encoder {
+	return jsonEncoder{}
+}
+
+func (jsonEncoder) Encode(buf *bytes.Buffer, entry *Entry) error {
+	buf.WriteByte('{')
+	writeJSONKeyValue(buf, "time", entry.Time.Format(time.RFC3339Nano), true)
+	writeJSONKeyValue(buf, "level", strings.ToLower(entry.Level.String()), false)
+	if len(entry.Prefixes) != 0 {
+		writeJSONKeyValue(buf, "prefix", strings.Join(entry.Prefixes, constant.Delimer), false)
+	}
+	writeJSONKeyValue(buf, "msg", entry.Message, false)
+	if entry.Caller != nil {
+		writeJSONKeyValue(buf, "caller", entry.Caller.String(), false)
+	}
+
+	for _, field := range entry.Fields {
+		writeJSONKeyValue(buf, fieldKey(field.Key, jsonReservedKeys), jsonValue(field.Value), false)
+	}
+
+	if len(entry.Errors) != 0 {
+		errs := make([]errorJSON, 0, len(entry.Errors))
+		for _, err := range entry.Errors {
+			errs = append(errs, newErrorJSON(err))
+		}
+		writeJSONKeyValue(buf, "errors", errs, false)
+	}
+
+	buf.WriteString("}\n")
+	return nil
+}
+
+func newErrorJSON(err error) errorJSON {
+	return errorJSON{Message: errors.GetErrorNoStack(err), Stack: errors.Frames(err)}
+}
+
+// jsonValue returns representation of v that should be marshaled.
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case error:
+		// Stack is written under "errors" key, errors of fields are included there.
+		return errors.GetErrorNoStack(v)
+	case time.Duration:
+		return v.String()
+	default:
+		return v
+	}
+}
+
+func writeJSONKeyValue(buf *bytes.Buffer, key string, value interface{}, first bool) {
+	if !first {
+		buf.WriteByte(',')
+	}
+
+	keyData, _ := json.Marshal(key)
+	buf.Write(keyData)
+	buf.WriteByte(':')
+
+	valueData, err := json.Marshal(value)
+	if err != nil {
+		// Not every value can be represented in json, fallback to its string representation.
+		valueData, _ = json.Marshal(fmt.Sprint(value))
+	}
+	buf.Write(valueData)
+}
@@ -0,0 +1,49 @@
+package log
+
+import (
+	"strings"
+
+	"github.com/corioders/gokit/errors"
+)
+
+type Level int32
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "DEBUG",
+	LevelInfo:  "INFO",
+	LevelWarn:  "WARN",
+	LevelError: "ERROR",
+	LevelFatal: "FATAL",
+}
+
+var (
+	ErrUnknownLevel = errors.New("Unknown log level")
+)
+
+func (l Level) String() string {
+	name, ok := levelNames[l]
+	if !ok {
+		return "UNKNOWN"
+	}
+	return name
+}
+
+// ParseLevel returns level with name s, case is ignored.
+// ParseLevel returns ErrUnknownLevel if there is no level named s.
+func ParseLevel(s string) (Level, error) {
+	for level, name := range levelNames {
+		if strings.EqualFold(name, s) {
+			return level, nil
+		}
+	}
+
+	return 0, errors.With(ErrUnknownLevel, "level", s)
+}
@@ -1,13 +1,13 @@
 package log
 
 import (
-	"fmt"
+	"bytes"
 	"io"
+	"os"
 	"sync"
+	"sync/atomic"
 
-	"github.com/corioders/gokit/constant"
-
-	"github.com/logrusorgru/aurora"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Logger interface {
@@ -16,43 +16,199 @@ type Logger interface {
 	Child(prefix string) Logger
 }
 
+// StructuredLogger is Logger with levels and fields, arguments of type Field passed to its methods are logged as fields.
+type StructuredLogger interface {
+	Logger
+
+	Debug(a ...interface{})
+	Warn(a ...interface{})
+	// Fatal logs message and exits the program with status 1.
+	Fatal(a ...interface{})
+	Log(level Level, a ...interface{})
+
+	// With returns child logger that logs fields with every message.
+	With(fields ...Field) StructuredLogger
+
+	// SetLevel sets minimum level of logged messages, it is shared with all child loggers.
+	SetLevel(level Level)
+	GetLevel() Level
+}
+
 type logger struct {
 	output   io.Writer
-	outputMu sync.Mutex
+	outputMu *sync.Mutex
+
+	encoder Encoder
+	caller  bool
+
+	spanEvents bool
+	span       trace.Span
 
-	prefix string
+	sampler *sampler
+
+	prefixes []string
+	fields   []Field
+	level    *int32
 }
 
-var (
-	statusError = aurora.Red("ERROR" + constant.Delimer).String()
-	statusInfo  = aurora.Blue("INFO" + constant.Delimer).String()
-)
+type options struct {
+	encoder    Encoder
+	caller     bool
+	level      Level
+	spanEvents bool
+	sampling   *SamplingOptions
+}
 
-func New(output io.Writer, prefix string) Logger {
-	return &logger{
-		outputMu: sync.Mutex{},
+// Option configures logger created by New.
+type Option func(o *options)
+
+// WithEncoder sets encoder of logged entries, by default console encoder is used.
+func WithEncoder(encoder Encoder) Option {
+	return func(o *options) {
+		o.encoder = encoder
+	}
+}
+
+// WithCaller enables logging location in source from which message was logged.
+func WithCaller() Option {
+	return func(o *options) {
+		o.caller = true
+	}
+}
+
+// WithLevel sets minimum level of logged messages, by default all messages are logged.
+func WithLevel(level Level) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// exit is called by Fatal, it is variable so it can be replaced in tests.
+var exit = os.Exit
+
+func New(output io.Writer, prefix string, opts ...Option) StructuredLogger {
+	o := &options{
+		encoder: NewConsoleEncoder(),
+		level:   LevelDebug,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	level := int32(o.level)
+	l := &logger{
+		outputMu: &sync.Mutex{},
 		output:   output,
 
-		prefix: aurora.Yellow(prefix + constant.Delimer).String(),
+		encoder: o.encoder,
+		caller:  o.caller,
+
+		spanEvents: o.spanEvents,
+
+		level: &level,
 	}
+	if o.sampling != nil {
+		l.sampler = newSampler(o.sampling)
+	}
+	if prefix != "" {
+		l.prefixes = []string{prefix}
+	}
+
+	return l
+}
+
+func (l *logger) Debug(a ...interface{}) {
+	l.log(LevelDebug, a)
 }
 
 func (l *logger) Info(a ...interface{}) {
-	message := statusInfo + l.prefix + fmt.Sprintln(a...)
-	l.log(message)
+	l.log(LevelInfo, a)
+}
+
+func (l *logger) Warn(a ...interface{}) {
+	l.log(LevelWarn, a)
 }
 
 func (l *logger) Error(a ...interface{}) {
-	message := statusError + l.prefix + fmt.Sprintln(a...)
-	l.log(message)
+	l.log(LevelError, a)
+}
+
+func (l *logger) Fatal(a ...interface{}) {
+	l.log(LevelFatal, a)
+	exit(1)
+}
+
+func (l *logger) Log(level Level, a ...interface{}) {
+	l.log(level, a)
 }
 
 func (l *logger) Child(prefix string) Logger {
-	return New(l.output, l.prefix+prefix)
+	child := l.clone()
+	child.prefixes = append(child.prefixes, prefix)
+	return child
+}
+
+func (l *logger) With(fields ...Field) StructuredLogger {
+	child := l.clone()
+	child.fields = append(child.fields, fields...)
+	return child
+}
+
+func (l *logger) SetLevel(level Level) {
+	atomic.StoreInt32(l.level, int32(level))
+}
+
+func (l *logger) GetLevel() Level {
+	return Level(atomic.LoadInt32(l.level))
 }
 
-func (l *logger) log(s string) {
+func (l *logger) clone() *logger {
+	return &logger{
+		output:   l.output,
+		outputMu: l.outputMu,
+
+		encoder: l.encoder,
+		caller:  l.caller,
+
+		spanEvents: l.spanEvents,
+		span:       l.span,
+
+		sampler: l.sampler,
+
+		prefixes: append(make([]string, 0, len(l.prefixes)+1), l.prefixes...),
+		fields:   append(make([]Field, 0, len(l.fields)), l.fields...),
+		level:    l.level,
+	}
+}
+
+// log must be called directly by exported logging methods, so caller is correct.
+func (l *logger) log(level Level, a []interface{}) {
+	if level < l.GetLevel() {
+		return
+	}
+
+	entry := newEntry(level, l.prefixes, append(make([]Field, 0, len(l.fields)), l.fields...), a)
+	if l.caller {
+		entry.Caller = newCaller(1)
+	}
+	if l.sampler != nil && !l.sampler.sample(l, entry) {
+		return
+	}
+	if l.span != nil {
+		addSpanEvent(l.span, entry)
+	}
+
+	l.write(entry)
+}
+
+func (l *logger) write(entry *Entry) {
+	buf := &bytes.Buffer{}
+	err := l.encoder.Encode(buf, entry)
+	if err != nil {
+		return
+	}
+
 	l.outputMu.Lock()
 	defer l.outputMu.Unlock()
-	l.output.Write([]byte(s))
+	l.output.Write(buf.Bytes())
 }
@@ -0,0 +1,112 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/corioders/gokit/errors"
+)
+
+// stripColors removes ANSI escape codes, so output can be compared.
+func stripColors(s string) string {
+	var sb strings.Builder
+	inEscape := false
+	for _, r := range s {
+		switch {
+		case r == '\x1b':
+			inEscape = true
+		case inEscape && r == 'm':
+			inEscape = false
+		case !inEscape:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func TestLog(t *testing.T) {
+	t.Run("message", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := New(buf, "prefix")
+
+		logger.Info("message", 1)
+		if stripColors(buf.String()) != "INFO: prefix: message 1\n" {
+			t.Fatal("Expected message to be logged with level and prefix, but got:", stripColors(buf.String()))
+		}
+	})
+
+	t.Run("child", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := New(buf, "parent").Child("child").Child("grandchild")
+
+		logger.Error("message")
+		if stripColors(buf.String()) != "ERROR: parent: child: grandchild: message\n" {
+			t.Fatal("Expected message to be logged with prefixes of all parents, but got:", stripColors(buf.String()))
+		}
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := New(buf, "").With(String("first", "1"))
+
+		logger.Warn("message", Int("second", 2))
+		if stripColors(buf.String()) != "WARN: message first=1 second=2\n" {
+			t.Fatal("Expected fields to be logged after message, but got:", stripColors(buf.String()))
+		}
+	})
+
+	t.Run("error field", func(t *testing.T) {
+		err := errors.With(errors.New("test error"), "id", 1)
+		entry := newEntry(LevelError, nil, nil, []interface{}{"message", Err(err)})
+
+		if len(entry.Errors) != 1 || entry.Errors[0] != err {
+			t.Fatal("Expected error passed as field to be recorded, but got:", entry.Errors)
+		}
+		if len(entry.Fields) != 2 || entry.Fields[1].Key != "id" {
+			t.Fatal("Expected fields attached to error to be recorded, but got:", entry.Fields)
+		}
+	})
+
+	t.Run("level", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := New(buf, "")
+		child := logger.Child("child")
+
+		logger.SetLevel(LevelWarn)
+		logger.Info("message")
+		child.Info("message")
+		if buf.Len() != 0 {
+			t.Fatal("Expected messages below minimum level not to be logged, but got:", stripColors(buf.String()))
+		}
+
+		child.Error("message")
+		if buf.Len() == 0 {
+			t.Fatal("Expected messages above minimum level to be logged")
+		}
+	})
+
+	t.Run("fatal", func(t *testing.T) {
+		exitCode := -1
+		originalExit := exit
+		exit = func(code int) { exitCode = code }
+		defer func() { exit = originalExit }()
+
+		New(&bytes.Buffer{}, "").Fatal("message")
+		if exitCode != 1 {
+			t.Fatal("Expected Fatal to exit with code 1, but got:", exitCode)
+		}
+	})
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("warn")
+	if err != nil || level != LevelWarn {
+		t.Fatal("Expected ParseLevel to return LevelWarn, but got:", level, err)
+	}
+
+	_, err = ParseLevel("unknown")
+	if err == nil {
+		t.Fatal("Expected error when parsing unknown level")
+	}
+}
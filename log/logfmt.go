@@ -0,0 +1,112 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/corioders/gokit/constant"
+	"github.com/corioders/gokit/errors"
+)
+
+type logfmtEncoder struct{}
+
+// logfmtReservedKeys are keys written by logfmtEncoder, fields must not override them.
+var logfmtReservedKeys = map[string]bool{
+	"time":        true,
+	"level":       true,
+	"prefix":      true,
+	"msg":         true,
+	"caller":      true,
+	"error":       true,
+	"error_stack": true,
+}
+
+// NewLogfmtEncoder creates encoder that writes entries as logfmt key=value pairs, one entry per line.
+// Errors are written under "error" key separated by "; " and their stacks under "error_stack" key
+// as comma separated file:line lists separated by ";".
+// Fields with keys used by encoder itself, e.g. "level", are written with "fields." prefix, e.g. "fields.level".
+func NewLogfmtEncoder() Encoder {
+	return logfmtEncoder{}
+}
+
+func (logfmtEncoder) Encode(buf *bytes.Buffer, entry *Entry) error {
+	writeLogfmtKeyValue(buf, "time", entry.Time.Format(time.RFC3339Nano), true)
+	writeLogfmtKeyValue(buf, "level", strings.ToLower(entry.Level.String()), false)
+	if len(entry.Prefixes) != 0 {
+		writeLogfmtKeyValue(buf, "prefix", strings.Join(entry.Prefixes, constant.Delimer), false)
+	}
+	writeLogfmtKeyValue(buf, "msg", entry.Message, false)
+	if entry.Caller != nil {
+		writeLogfmtKeyValue(buf, "caller", entry.Caller.String(), false)
+	}
+
+	for _, field := range entry.Fields {
+		value := fmt.Sprint(field.Value)
+		if err, ok := field.Value.(error); ok {
+			// Errors of fields are in entry.Errors, error logged with Err would be written twice under "error" key.
+			if field.Key == "error" {
+				continue
+			}
+			value = errors.GetErrorNoStack(err)
+		}
+		writeLogfmtKeyValue(buf, fieldKey(field.Key, logfmtReservedKeys), value, false)
+	}
+
+	if len(entry.Errors) != 0 {
+		writeLogfmtErrors(buf, entry.Errors)
+	}
+
+	buf.WriteByte('\n')
+	return nil
+}
+
+// writeLogfmtErrors writes messages of errs and their stacks, each under single key, so keys are not repeated.
+func writeLogfmtErrors(buf *bytes.Buffer, errs []error) {
+	messages := make([]string, 0, len(errs))
+	stacks := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, errors.GetErrorNoStack(err))
+
+		frames := errors.Frames(err)
+		if len(frames) == 0 {
+			continue
+		}
+		stack := make([]string, 0, len(frames))
+		for _, frame := range frames {
+			stack = append(stack, fmt.Sprintf("%s:%d", frame.File, frame.Line))
+		}
+		stacks = append(stacks, strings.Join(stack, ","))
+	}
+
+	writeLogfmtKeyValue(buf, "error", strings.Join(messages, "; "), false)
+	if len(stacks) != 0 {
+		writeLogfmtKeyValue(buf, "error_stack", strings.Join(stacks, ";"), false)
+	}
+}
+
+func writeLogfmtKeyValue(buf *bytes.Buffer, key string, value string, first bool) {
+	if !first {
+		buf.WriteByte(' ')
+	}
+
+	buf.WriteString(logfmtQuote(key))
+	buf.WriteByte('=')
+	buf.WriteString(logfmtQuote(value))
+}
+
+// logfmtQuote quotes s if it is empty or contains characters that are not allowed in unquoted logfmt value.
+func logfmtQuote(s string) string {
+	if s == "" {
+		return `""`
+	}
+
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r >= 0x7f {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
@@ -0,0 +1,167 @@
+// Package logtest provides logger for tests that records logged entries, so tests can assert on them.
+package logtest
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/corioders/gokit/errors"
+	"github.com/corioders/gokit/log"
+)
+
+// Logger is log.StructuredLogger that records entries logged by it and all of its children,
+// output is written with t.Log, so it is shown only when test fails or is run verbosely.
+type Logger struct {
+	log.StructuredLogger
+
+	t        testing.TB
+	recorder *recorder
+}
+
+// New creates Logger, options are passed to log.New, but entries are always written with console encoder.
+func New(t testing.TB, options ...log.Option) *Logger {
+	r := &recorder{encoder: log.NewConsoleEncoder()}
+	options = append(options, log.WithEncoder(r))
+
+	w := &testWriter{t: t}
+	t.Cleanup(w.finish)
+
+	return &Logger{
+		StructuredLogger: log.New(w, "", options...),
+
+		t:        t,
+		recorder: r,
+	}
+}
+
+// Entries returns entries logged so far in order they were logged.
+func (l *Logger) Entries() []log.Entry {
+	l.recorder.mu.Lock()
+	defer l.recorder.mu.Unlock()
+
+	return append(make([]log.Entry, 0, len(l.recorder.entries)), l.recorder.entries...)
+}
+
+// Filter returns logged entries for which fn returns true.
+func (l *Logger) Filter(fn func(entry *log.Entry) bool) []log.Entry {
+	var entries []log.Entry
+	for _, entry := range l.Entries() {
+		if fn(&entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Reset removes recorded entries.
+func (l *Logger) Reset() {
+	l.recorder.mu.Lock()
+	defer l.recorder.mu.Unlock()
+
+	l.recorder.entries = nil
+}
+
+// AssertMessage fails the test if no entry with message containing message was logged.
+func (l *Logger) AssertMessage(message string) {
+	l.t.Helper()
+
+	entries := l.Filter(func(entry *log.Entry) bool {
+		return strings.Contains(entry.Message, message)
+	})
+	if len(entries) == 0 {
+		l.t.Errorf("Expected message %q to be logged, but got:\n%s", message, l.String())
+	}
+}
+
+// AssertError fails the test if no entry with error matching target by errors.Is was logged,
+// errors logged as fields, e.g. with log.Err, are also matched.
+func (l *Logger) AssertError(target error) {
+	l.t.Helper()
+
+	entries := l.Filter(func(entry *log.Entry) bool {
+		for _, err := range entry.Errors {
+			if errors.Is(err, target) {
+				return true
+			}
+		}
+		return false
+	})
+	if len(entries) == 0 {
+		l.t.Errorf("Expected error %q to be logged, but got:\n%s", errors.GetErrorNoStack(target), l.String())
+	}
+}
+
+// AssertNoErrors fails the test if any entry with level LevelError or higher was logged.
+func (l *Logger) AssertNoErrors() {
+	l.t.Helper()
+
+	entries := l.Filter(func(entry *log.Entry) bool {
+		return entry.Level >= log.LevelError
+	})
+	if len(entries) != 0 {
+		l.t.Errorf("Expected no errors to be logged, but got:\n%s", l.String())
+	}
+}
+
+// String returns logged entries in format: LEVEL: prefixes: message key=value, one entry per line.
+func (l *Logger) String() string {
+	var sb strings.Builder
+	for _, entry := range l.Entries() {
+		sb.WriteString(entry.Level.String())
+		sb.WriteString(": ")
+		for _, prefix := range entry.Prefixes {
+			sb.WriteString(prefix)
+			sb.WriteString(": ")
+		}
+		sb.WriteString(entry.Message)
+		for _, field := range entry.Fields {
+			sb.WriteString(fmt.Sprintf(" %s=%v", field.Key, field.Value))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// recorder is log.Encoder that records entries and encodes them with encoder.
+type recorder struct {
+	encoder log.Encoder
+
+	mu      sync.Mutex
+	entries []log.Entry
+}
+
+func (r *recorder) Encode(buf *bytes.Buffer, entry *log.Entry) error {
+	r.mu.Lock()
+	r.entries = append(r.entries, *entry)
+	r.mu.Unlock()
+
+	return r.encoder.Encode(buf, entry)
+}
+
+// testWriter writes with t.Log, writes after the test finished are dropped, because t.Log panics then.
+type testWriter struct {
+	t testing.TB
+
+	mu       sync.Mutex
+	finished bool
+}
+
+func (w *testWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.finished {
+		w.t.Log(strings.TrimSuffix(string(p), "\n"))
+	}
+	return len(p), nil
+}
+
+func (w *testWriter) finish() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.finished = true
+}
@@ -0,0 +1,131 @@
+package logtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/corioders/gokit/errors"
+	"github.com/corioders/gokit/log"
+)
+
+// recordingT records failures, logs and cleanups instead of passing them to the test.
+type recordingT struct {
+	testing.TB
+	failed   bool
+	failure  string
+	logs     int
+	cleanups []func()
+}
+
+func (t *recordingT) Helper()                 {}
+func (t *recordingT) Log(args ...interface{}) { t.logs++ }
+func (t *recordingT) Cleanup(fn func())       { t.cleanups = append(t.cleanups, fn) }
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.failed = true
+	t.failure = fmt.Sprintf(format, args...)
+}
+
+func TestLogger(t *testing.T) {
+	t.Run("entries", func(t *testing.T) {
+		logger := New(t)
+		logger.Child("child").(log.StructuredLogger).With(log.Int("count", 1)).Warn("message")
+
+		entries := logger.Entries()
+		if len(entries) != 1 {
+			t.Fatal("Expected one entry to be recorded, but got:", len(entries))
+		}
+		entry := entries[0]
+		if entry.Level != log.LevelWarn || len(entry.Prefixes) != 1 || entry.Prefixes[0] != "child" || entry.Message != "message" {
+			t.Fatal("Expected entry to have level, prefixes and message, but got:", entry)
+		}
+		if len(entry.Fields) != 1 || entry.Fields[0].Key != "count" || entry.Fields[0].Value != 1 {
+			t.Fatal("Expected entry to have fields, but got:", entry.Fields)
+		}
+		if logger.String() != "WARN: child: message count=1\n" {
+			t.Fatal("Expected entries to be formatted, but got:", logger.String())
+		}
+
+		logger.Reset()
+		if len(logger.Entries()) != 0 {
+			t.Fatal("Expected no entries after Reset")
+		}
+	})
+
+	t.Run("assert message", func(t *testing.T) {
+		rt := &recordingT{}
+		logger := New(rt)
+		logger.Info("some message")
+
+		logger.AssertMessage("some")
+		if rt.failed {
+			t.Fatal("Expected logged message to be found")
+		}
+
+		logger.AssertMessage("other")
+		if !rt.failed {
+			t.Fatal("Expected not logged message to fail the test")
+		}
+	})
+
+	t.Run("assert error", func(t *testing.T) {
+		rt := &recordingT{}
+		logger := New(rt)
+		expectedErr := errors.New("Test error")
+		logger.Error(errors.WithMessage(expectedErr, "Wrapped"))
+
+		logger.AssertError(expectedErr)
+		if rt.failed {
+			t.Fatal("Expected logged error to be found")
+		}
+
+		fieldErr := errors.New("Field error")
+		logger.Error("failed", log.Err(fieldErr))
+		logger.AssertError(fieldErr)
+		if rt.failed {
+			t.Fatal("Expected error logged with log.Err to be found")
+		}
+
+		logger.AssertError(errors.New("Other error"))
+		if !rt.failed {
+			t.Fatal("Expected not logged error to fail the test")
+		}
+
+		logger.AssertError(fmt.Errorf("Plain error"))
+		if !strings.Contains(rt.failure, `"Plain error"`) {
+			t.Fatal("Expected failure to contain message of not logged error, but got:", rt.failure)
+		}
+	})
+
+	t.Run("log after test finished", func(t *testing.T) {
+		rt := &recordingT{}
+		logger := New(rt)
+		logger.Info("message")
+		for _, fn := range rt.cleanups {
+			fn()
+		}
+
+		logger.Info("message")
+		if rt.logs != 1 {
+			t.Fatal("Expected messages logged after test finished to be dropped, but got logs:", rt.logs)
+		}
+	})
+
+	t.Run("assert no errors", func(t *testing.T) {
+		rt := &recordingT{}
+		logger := New(rt)
+		logger.Warn("warning")
+
+		logger.AssertNoErrors()
+		if rt.failed {
+			t.Fatal("Expected warning not to fail the test")
+		}
+
+		logger.Error("error")
+		logger.AssertNoErrors()
+		if !rt.failed {
+			t.Fatal("Expected logged error to fail the test")
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+type SamplingOptions struct {
+	// Interval is the period in which messages are counted, zero means one second.
+	Interval time.Duration
+
+	// First is the number of similar messages logged in each interval before sampling starts.
+	First uint64
+
+	// Thereafter means that after First messages only every Thereafter-th similar message is logged,
+	// zero means that all of them are suppressed.
+	Thereafter uint64
+}
+
+// WithSampling limits the number of similar messages logged in each interval,
+// messages are similar if they have the same level, prefixes and message, which for errors contains message of the error.
+// At the end of interval in which messages were suppressed, summary with their count is logged.
+// Sampling is shared with all child loggers, fatal messages are never sampled.
+func WithSampling(samplingOptions *SamplingOptions) Option {
+	return func(o *options) {
+		o.sampling = samplingOptions
+	}
+}
+
+type sampler struct {
+	interval   time.Duration
+	first      uint64
+	thereafter uint64
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counters    map[string]*sampleCounter
+	timer       *time.Timer
+}
+
+type sampleCounter struct {
+	count      uint64
+	suppressed uint64
+
+	// logger and entry are the last suppressed message, they are used to log summary.
+	logger *logger
+	entry  *Entry
+}
+
+func newSampler(options *SamplingOptions) *sampler {
+	interval := options.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	return &sampler{
+		interval:   interval,
+		first:      options.First,
+		thereafter: options.Thereafter,
+		counters:   map[string]*sampleCounter{},
+	}
+}
+
+// sample reports whether entry logged by l should be logged.
+func (s *sampler) sample(l *logger, entry *Entry) bool {
+	if entry.Level >= LevelFatal {
+		return true
+	}
+
+	s.mu.Lock()
+	summaries := s.rotate(entry.Time)
+
+	key := entry.Level.String() + "\x00" + strings.Join(entry.Prefixes, "\x00") + "\x00" + entry.Message
+	counter, ok := s.counters[key]
+	if !ok {
+		counter = &sampleCounter{}
+		s.counters[key] = counter
+	}
+	counter.count++
+
+	allowed := counter.count <= s.first || (s.thereafter != 0 && (counter.count-s.first)%s.thereafter == 0)
+	if !allowed {
+		counter.suppressed++
+		counter.logger = l
+		counter.entry = entry
+		if s.timer == nil {
+			s.timer = time.AfterFunc(s.windowStart.Add(s.interval).Sub(entry.Time), s.flush)
+		}
+	}
+	s.mu.Unlock()
+
+	logSummaries(summaries)
+	return allowed
+}
+
+// flush is called by timer at the end of interval in which messages were suppressed.
+func (s *sampler) flush() {
+	s.mu.Lock()
+	s.timer = nil
+	summaries := s.rotate(time.Now())
+	s.mu.Unlock()
+
+	logSummaries(summaries)
+}
+
+// rotate starts new interval if the current one ended before now and returns counters of the ended interval
+// that have suppressed messages, s.mu must be held.
+func (s *sampler) rotate(now time.Time) []*sampleCounter {
+	if now.Sub(s.windowStart) < s.interval {
+		return nil
+	}
+
+	var summaries []*sampleCounter
+	for _, counter := range s.counters {
+		if counter.suppressed != 0 {
+			summaries = append(summaries, counter)
+		}
+	}
+
+	s.windowStart = now
+	s.counters = map[string]*sampleCounter{}
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	return summaries
+}
+
+func logSummaries(summaries []*sampleCounter) {
+	for _, counter := range summaries {
+		entry := &Entry{
+			Time:     time.Now(),
+			Level:    counter.entry.Level,
+			Prefixes: counter.entry.Prefixes,
+			Message:  fmt.Sprintf("Suppressed %d similar messages", counter.suppressed),
+			Fields:   append(append(make([]Field, 0, len(counter.entry.Fields)+1), counter.entry.Fields...), String("sampled_message", counter.entry.Message)),
+		}
+		counter.logger.write(entry)
+	}
+}
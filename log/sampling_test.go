@@ -0,0 +1,77 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is bytes.Buffer safe for use by logger and summaries logged from timer.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return stripColors(b.buf.String())
+}
+
+func TestSampling(t *testing.T) {
+	t.Run("first and thereafter", func(t *testing.T) {
+		buf := &syncBuffer{}
+		logger := New(buf, "", WithSampling(&SamplingOptions{Interval: time.Hour, First: 2, Thereafter: 3}))
+
+		for i := 0; i < 8; i++ {
+			logger.Error("message")
+		}
+		logger.Error("other")
+
+		// Messages 1, 2 and 5, 8.
+		if strings.Count(buf.String(), "message") != 4 {
+			t.Fatal("Expected first 2 messages and then every 3rd to be logged, but got:", buf.String())
+		}
+		if !strings.Contains(buf.String(), "other") {
+			t.Fatal("Expected different message to be counted separately, but got:", buf.String())
+		}
+	})
+
+	t.Run("children", func(t *testing.T) {
+		buf := &syncBuffer{}
+		logger := New(buf, "", WithSampling(&SamplingOptions{Interval: time.Hour, First: 1}))
+
+		logger.Child("first").Error("message")
+		logger.Child("first").Error("message")
+		logger.Child("second").Error("message")
+
+		if buf.String() != "ERROR: first: message\nERROR: second: message\n" {
+			t.Fatal("Expected messages with different prefixes to be counted separately, but got:", buf.String())
+		}
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		buf := &syncBuffer{}
+		logger := New(buf, "", WithSampling(&SamplingOptions{Interval: 10 * time.Millisecond, First: 1}))
+
+		for i := 0; i < 5; i++ {
+			logger.Warn("message")
+		}
+
+		deadline := time.Now().Add(time.Second)
+		for !strings.Contains(buf.String(), "Suppressed") && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if !strings.Contains(buf.String(), "WARN: Suppressed 4 similar messages sampled_message=message") {
+			t.Fatal("Expected summary of suppressed messages to be logged, but got:", buf.String())
+		}
+	})
+}
@@ -0,0 +1,212 @@
+// Package sink provides writers that can be used as output of log.New.
+package sink
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/corioders/gokit/application"
+	"github.com/corioders/gokit/errors"
+)
+
+// Policy decides what Async does with message when its queue is full.
+type Policy int
+
+const (
+	// PolicyBlock blocks the writer until there is space in the queue.
+	PolicyBlock Policy = iota
+	// PolicyDrop drops the message and counts it as dropped.
+	PolicyDrop
+)
+
+// DefaultQueueSize is the number of messages Async can hold, if no QueueSize is provided.
+const DefaultQueueSize = 1024
+
+type AsyncOptions struct {
+	// QueueSize is the number of messages that can wait for being written, zero means DefaultQueueSize.
+	QueueSize int
+
+	// Policy is used when queue is full.
+	Policy Policy
+
+	// FlushTimeout is maximum time that flushing queued messages can take while application is stopping,
+	// zero means that flushing is bounded only by application's stop deadline.
+	FlushTimeout time.Duration
+}
+
+// Async is io.Writer that writes messages to underlying writer in background goroutine,
+// so slow output doesn't stall the logging goroutine.
+type Async struct {
+	w io.Writer
+
+	queue chan asyncMessage
+	done  chan struct{}
+
+	policy  Policy
+	dropped uint64
+
+	// closing is closed when Close is called, it unblocks writers waiting for space in the queue.
+	closing   chan struct{}
+	closeOnce sync.Once
+	// stopped is closed when Close returns, after that messages are written directly to w.
+	stopped  chan struct{}
+	stopOnce sync.Once
+
+	// closedMu guards sending to queue against closing it, it is never held while waiting.
+	closedMu sync.RWMutex
+	closed   bool
+	// writeMu serializes writes to w, background goroutine may still be writing when Close gives up waiting for it.
+	writeMu sync.Mutex
+}
+
+type asyncMessage struct {
+	p []byte
+	// flushed is closed when all messages queued before are written, it is set only for flush requests.
+	flushed chan struct{}
+}
+
+// NewAsync creates Async writing to w, queued messages are flushed when application is stopping.
+// Messages written after the application stopped are written to w synchronously.
+func NewAsync(sr application.StopRegistrar, w io.Writer, options *AsyncOptions) (*Async, error) {
+	if options == nil {
+		options = &AsyncOptions{}
+	}
+
+	queueSize := options.QueueSize
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+
+	a := &Async{
+		w:       w,
+		queue:   make(chan asyncMessage, queueSize),
+		done:    make(chan struct{}),
+		policy:  options.Policy,
+		closing: make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go a.run()
+
+	err := sr.RegisterOnStopContext("Flush async log writer", a.Close, application.WithStopTimeout(options.FlushTimeout))
+	if err != nil {
+		a.Close(context.Background())
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (a *Async) run() {
+	defer close(a.done)
+
+	for message := range a.queue {
+		if message.flushed != nil {
+			close(message.flushed)
+			continue
+		}
+
+		a.writeMu.Lock()
+		a.w.Write(message.p)
+		a.writeMu.Unlock()
+	}
+}
+
+// Write queues copy of p for writing, it never returns error of the underlying writer.
+// When queue is full and policy is PolicyDrop p is dropped, but Write still reports success,
+// so callers don't retry, dropped messages are counted in Dropped.
+func (a *Async) Write(p []byte) (int, error) {
+	a.closedMu.RLock()
+	if a.closed {
+		a.closedMu.RUnlock()
+		return a.writeDirect(p)
+	}
+
+	message := asyncMessage{p: append(make([]byte, 0, len(p)), p...)}
+	if a.policy == PolicyDrop {
+		select {
+		case a.queue <- message:
+		default:
+			atomic.AddUint64(&a.dropped, 1)
+		}
+		a.closedMu.RUnlock()
+		return len(p), nil
+	}
+
+	select {
+	case a.queue <- message:
+		a.closedMu.RUnlock()
+		return len(p), nil
+	case <-a.closing:
+		a.closedMu.RUnlock()
+		return a.writeDirect(p)
+	}
+}
+
+// writeDirect writes p to the underlying writer once Close returned,
+// so messages queued before closing are written first, unless Close gave up waiting for them.
+func (a *Async) writeDirect(p []byte) (int, error) {
+	<-a.stopped
+
+	a.writeMu.Lock()
+	defer a.writeMu.Unlock()
+	return a.w.Write(p)
+}
+
+// Dropped returns the number of messages dropped because queue was full.
+func (a *Async) Dropped() uint64 {
+	return atomic.LoadUint64(&a.dropped)
+}
+
+// Flush waits until all messages queued before calling it are written or ctx is done.
+func (a *Async) Flush(ctx context.Context) error {
+	a.closedMu.RLock()
+	if a.closed {
+		a.closedMu.RUnlock()
+		return nil
+	}
+
+	message := asyncMessage{flushed: make(chan struct{})}
+	select {
+	case a.queue <- message:
+		a.closedMu.RUnlock()
+	case <-a.closing:
+		a.closedMu.RUnlock()
+		return nil
+	case <-ctx.Done():
+		a.closedMu.RUnlock()
+		return errors.WithStack(ctx.Err())
+	}
+
+	select {
+	case <-message.flushed:
+		return nil
+	case <-ctx.Done():
+		return errors.WithStack(ctx.Err())
+	}
+}
+
+// Close writes all queued messages and stops background goroutine, it returns when that is done or ctx is done.
+// Messages written after Close are written to the underlying writer synchronously.
+func (a *Async) Close(ctx context.Context) error {
+	defer a.stopOnce.Do(func() { close(a.stopped) })
+
+	a.closeOnce.Do(func() {
+		// Unblock writers waiting for space in the queue first, so they release closedMu.
+		close(a.closing)
+
+		a.closedMu.Lock()
+		a.closed = true
+		close(a.queue)
+		a.closedMu.Unlock()
+	})
+
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return errors.WithStack(ctx.Err())
+	}
+}
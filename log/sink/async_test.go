@@ -0,0 +1,151 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/corioders/gokit/application"
+	"github.com/corioders/gokit/log"
+)
+
+// blockingWriter blocks every write until unblock is closed.
+type blockingWriter struct {
+	unblock chan struct{}
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.unblock
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *blockingWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestAsync(t *testing.T) {
+	t.Run("flush on stop", func(t *testing.T) {
+		app := application.New(log.New(io.Discard, ""))
+		w := &blockingWriter{unblock: make(chan struct{})}
+		async, err := NewAsync(app, w, nil)
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		logger := log.New(async, "")
+		for i := 0; i < 10; i++ {
+			logger.Info("message")
+		}
+		close(w.unblock)
+
+		err = app.Stop()
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		if strings.Count(w.String(), "message") != 10 {
+			t.Fatal("Expected all messages to be written on stop, but got:", w.String())
+		}
+
+		logger.Info("after stop")
+		if !strings.Contains(w.String(), "after stop") {
+			t.Fatal("Expected message written after stop to be written synchronously, but got:", w.String())
+		}
+	})
+
+	t.Run("drop", func(t *testing.T) {
+		app := application.New(log.New(io.Discard, ""))
+		w := &blockingWriter{unblock: make(chan struct{})}
+		async, err := NewAsync(app, w, &AsyncOptions{QueueSize: 1, Policy: PolicyDrop})
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		for i := 0; i < 10; i++ {
+			async.Write([]byte("message\n"))
+		}
+		// At most one message is being written and one is queued.
+		if async.Dropped() < 8 {
+			t.Fatal("Expected messages to be dropped when queue is full, but got dropped:", async.Dropped())
+		}
+		close(w.unblock)
+
+		err = async.Flush(context.Background())
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		if uint64(strings.Count(w.String(), "message")) != 10-async.Dropped() {
+			t.Fatal("Expected all not dropped messages to be written, but got:", w.String())
+		}
+	})
+
+	t.Run("flush timeout", func(t *testing.T) {
+		app := application.New(log.New(io.Discard, ""))
+		w := &blockingWriter{unblock: make(chan struct{})}
+		defer close(w.unblock)
+		async, err := NewAsync(app, w, nil)
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		async.Write([]byte("message\n"))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err = async.Flush(ctx)
+		if err == nil {
+			t.Fatal("Expected error when ctx is done before messages are written")
+		}
+	})
+	t.Run("close timeout with blocked writer", func(t *testing.T) {
+		app := application.New(log.New(io.Discard, ""))
+		w := &blockingWriter{unblock: make(chan struct{})}
+		async, err := NewAsync(app, w, &AsyncOptions{QueueSize: 1})
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		// First message is being written, second is queued and third blocks until there is space in the queue.
+		async.Write([]byte("first\n"))
+		async.Write([]byte("second\n"))
+		written := make(chan struct{})
+		go func() {
+			async.Write([]byte("third\n"))
+			close(written)
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		closed := make(chan error)
+		go func() { closed <- async.Close(ctx) }()
+
+		select {
+		case err := <-closed:
+			if err == nil {
+				t.Fatal("Expected error when messages can't be written before ctx is done")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected Close to return when ctx is done")
+		}
+
+		close(w.unblock)
+		select {
+		case <-written:
+		case <-time.After(time.Second):
+			t.Fatal("Expected blocked writer to return after Close")
+		}
+		if !strings.Contains(w.String(), "third") {
+			t.Fatal("Expected message of blocked writer to be written, but got:", w.String())
+		}
+	})
+}
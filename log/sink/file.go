@@ -0,0 +1,371 @@
+package sink
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/corioders/gokit/application"
+	"github.com/corioders/gokit/errors"
+)
+
+type FileOptions struct {
+	// Path is the path of the log file, rotated files are stored next to it with time of rotation appended to the name.
+	Path string
+
+	// MaxSize is the size in bytes after which the file is rotated, zero means no size limit.
+	MaxSize int64
+
+	// MaxAge is the time after which the file is rotated, it is measured from opening the file, zero means no age limit.
+	MaxAge time.Duration
+
+	// Compress enables gzip compression of rotated files.
+	Compress bool
+
+	// MaxBackups is the number of rotated files that are retained, zero means that all of them are retained.
+	MaxBackups int
+
+	// ReopenOnSIGHUP enables reopening the file when process receives SIGHUP,
+	// so file can be rotated by external tool like logrotate.
+	ReopenOnSIGHUP bool
+}
+
+func (o *FileOptions) validate() error {
+	if o.Path == "" {
+		return ErrFilePathNotProvided
+	}
+
+	return nil
+}
+
+var (
+	ErrFilePathNotProvided = errors.New("File path not provided")
+	ErrFileClosed          = errors.New("File is closed")
+)
+
+// backupTimeFormat is appended to the name of rotated file, it sorts in chronological order.
+const backupTimeFormat = "2006-01-02T15-04-05.000000000"
+
+// File is io.Writer writing to file that is rotated by size and age.
+type File struct {
+	options FileOptions
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	closed   bool
+
+	signals chan os.Signal
+	// background waits for reopening on signal and compressing rotated files.
+	background sync.WaitGroup
+	// backupsMu serializes compression and removal of rotated files.
+	backupsMu sync.Mutex
+	errs      errors.Collector
+}
+
+// NewFile opens file for appending, it is closed when application is stopping.
+func NewFile(sr application.StopRegistrar, options *FileOptions) (*File, error) {
+	err := options.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	f := &File{options: *options}
+	err = f.open()
+	if err != nil {
+		return nil, err
+	}
+
+	if options.ReopenOnSIGHUP {
+		f.signals = make(chan os.Signal, 1)
+		signal.Notify(f.signals, syscall.SIGHUP)
+
+		f.background.Add(1)
+		go func() {
+			defer f.background.Done()
+			for range f.signals {
+				f.errs.Append(f.Reopen())
+			}
+		}()
+	}
+
+	err = sr.RegisterOnStopContext(fmt.Sprintf("Close log file %s", options.Path), f.Close)
+	if err != nil {
+		return nil, errors.Append(err, f.Close(context.Background()))
+	}
+
+	return f, nil
+}
+
+// open opens the file, f.mu must be held.
+func (f *File) open() error {
+	file, err := os.OpenFile(f.options.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return errors.WithStack(err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = time.Now()
+	return nil
+}
+
+// Write writes p to the file, rotating it first if needed.
+// After Close, p is appended to the file directly without rotation,
+// so messages logged by other stop handlers are not lost.
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return f.writeClosed(p)
+	}
+
+	// File is nil when reopening it failed, opening is retried, so one failure doesn't stop logging.
+	if f.file == nil {
+		err := f.open()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if f.shouldRotate(len(p)) {
+		err := f.rotate()
+		if err != nil {
+			if f.file == nil {
+				return 0, err
+			}
+			// Rotation failed, but file is open again, so p is written and rotation is retried with the next write.
+			f.errs.Append(err)
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	if err != nil {
+		return n, errors.WithStack(err)
+	}
+	return n, nil
+}
+
+// writeClosed opens the file just for writing p, f.mu must be held.
+func (f *File) writeClosed(p []byte) (int, error) {
+	file, err := os.OpenFile(f.options.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	n, err := file.Write(p)
+	err = errors.Append(err, file.Close())
+	if err != nil {
+		return n, errors.WithStack(err)
+	}
+	return n, nil
+}
+
+// shouldRotate reports whether file should be rotated before writing n bytes, f.mu must be held.
+func (f *File) shouldRotate(n int) bool {
+	// Empty file is never rotated, so message larger than MaxSize is still written.
+	if f.size == 0 {
+		return false
+	}
+
+	if f.options.MaxSize > 0 && f.size+int64(n) > f.options.MaxSize {
+		return true
+	}
+	return f.options.MaxAge > 0 && time.Since(f.openedAt) >= f.options.MaxAge
+}
+
+// Rotate closes the file, renames it and opens new file.
+func (f *File) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return ErrFileClosed
+	}
+	return f.rotate()
+}
+
+// rotate must be called with f.mu held, f.file is nil if the file couldn't be opened again.
+func (f *File) rotate() error {
+	err := f.closeFile()
+	if err != nil {
+		return err
+	}
+
+	backup := f.options.Path + "." + time.Now().Format(backupTimeFormat)
+	renameErr := os.Rename(f.options.Path, backup)
+	err = f.open()
+	if renameErr != nil {
+		return errors.Append(errors.WithStack(renameErr), err)
+	}
+	if err != nil {
+		return err
+	}
+
+	f.background.Add(1)
+	go func() {
+		defer f.background.Done()
+		f.backupsMu.Lock()
+		defer f.backupsMu.Unlock()
+
+		if f.options.Compress {
+			f.errs.Append(compress(backup))
+		}
+		f.errs.Append(f.removeOldBackups())
+	}()
+
+	return nil
+}
+
+// Reopen closes and opens the file again, it is used after the file was moved by external tool.
+func (f *File) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return ErrFileClosed
+	}
+
+	err := f.closeFile()
+	if err != nil {
+		return err
+	}
+	return f.open()
+}
+
+// closeFile closes f.file and sets it to nil, so it is opened again by the next write, f.mu must be held.
+func (f *File) closeFile() error {
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Close closes the file and waits for compression of rotated files or ctx being done,
+// errors of reopening and compression that happened in background are also returned.
+// Writes after Close open the file for each write.
+func (f *File) Close(ctx context.Context) error {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return nil
+	}
+	f.closed = true
+	err := f.closeFile()
+	f.mu.Unlock()
+
+	if f.signals != nil {
+		signal.Stop(f.signals)
+		close(f.signals)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		f.background.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return errors.Append(err, f.errs.Err())
+	case <-ctx.Done():
+		return errors.Append(err, errors.WithStack(ctx.Err()))
+	}
+}
+
+// compress replaces file at path with its gzip compressed version.
+func compress(path string) error {
+	src, err := os.Open(path)
+	if os.IsNotExist(err) {
+		// Backup was already removed because of retention.
+		return nil
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	gw := gzip.NewWriter(dst)
+	_, err = io.Copy(gw, src)
+	err = errors.Append(err, gw.Close(), dst.Close())
+	if err != nil {
+		os.Remove(path + ".gz")
+		return errors.WithStack(err)
+	}
+
+	err = os.Remove(path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// removeOldBackups removes the oldest rotated files, so at most MaxBackups are retained, f.backupsMu must be held.
+func (f *File) removeOldBackups() error {
+	if f.options.MaxBackups <= 0 {
+		return nil
+	}
+
+	matches, err := filepath.Glob(f.options.Path + ".*")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	// Backup is compressed when both versions exist, it must be counted once.
+	backups := map[string]struct{}{}
+	for _, match := range matches {
+		name := strings.TrimSuffix(match, ".gz")
+		// Skip files that are not backups, but have similar name.
+		_, err := time.Parse(backupTimeFormat, strings.TrimPrefix(name, f.options.Path+"."))
+		if err != nil {
+			continue
+		}
+		backups[name] = struct{}{}
+	}
+
+	names := make([]string, 0, len(backups))
+	for name := range backups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs errors.Collector
+	for i := 0; i < len(names)-f.options.MaxBackups; i++ {
+		for _, name := range []string{names[i], names[i] + ".gz"} {
+			err := os.Remove(name)
+			if err != nil && !os.IsNotExist(err) {
+				errs.Append(errors.WithStack(err))
+			}
+		}
+	}
+	return errs.Err()
+}
@@ -0,0 +1,171 @@
+package sink
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/corioders/gokit/application"
+	"github.com/corioders/gokit/errors"
+	"github.com/corioders/gokit/log"
+)
+
+func readDir(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal("Expected no error, but got:", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestFile(t *testing.T) {
+	t.Run("no path", func(t *testing.T) {
+		app := application.New(log.New(io.Discard, ""))
+		_, err := NewFile(app, &FileOptions{})
+		if !errors.Is(err, ErrFilePathNotProvided) {
+			t.Fatal("Expected ErrFilePathNotProvided, but got:", err)
+		}
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		dir := t.TempDir()
+		app := application.New(log.New(io.Discard, ""))
+		f, err := NewFile(app, &FileOptions{Path: filepath.Join(dir, "app.log"), MaxSize: 10, MaxBackups: 2, Compress: true})
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		for i := 0; i < 5; i++ {
+			_, err := f.Write([]byte("message\n"))
+			if err != nil {
+				t.Fatal("Expected no error, but got:", err)
+			}
+		}
+
+		err = app.Stop()
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		names := readDir(t, dir)
+		if len(names) != 3 {
+			t.Fatal("Expected log file and 2 backups to be retained, but got:", names)
+		}
+		for _, name := range names {
+			if name != "app.log" && !strings.HasSuffix(name, ".gz") {
+				t.Fatal("Expected backups to be compressed, but got:", names)
+			}
+		}
+
+		_, err = f.Write([]byte("after stop\n"))
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "app.log"))
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		if !strings.HasSuffix(string(data), "after stop\n") {
+			t.Fatal("Expected message written after stop to be appended to the file, but got:", string(data))
+		}
+	})
+
+	t.Run("reopen", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.log")
+		app := application.New(log.New(io.Discard, ""))
+		f, err := NewFile(app, &FileOptions{Path: path})
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		defer app.Stop()
+
+		logger := log.New(f, "")
+		logger.Info("first")
+
+		err = os.Rename(path, path+".old")
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		err = f.Reopen()
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		logger.Info("second")
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		if strings.Contains(string(content), "first") || !strings.Contains(string(content), "second") {
+			t.Fatal("Expected messages after reopen to be written to new file, but got:", string(content))
+		}
+	})
+	t.Run("rotation failure", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "logs")
+		path := filepath.Join(dir, "app.log")
+		err := os.Mkdir(dir, 0755)
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		app := application.New(log.New(io.Discard, ""))
+		f, err := NewFile(app, &FileOptions{Path: path, MaxSize: 10})
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		_, err = f.Write([]byte("first\n"))
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		// Renaming removed file fails, but the file is opened again, so message is still written.
+		err = os.Remove(path)
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		_, err = f.Write([]byte("second\n"))
+		if err != nil {
+			t.Fatal("Expected message to be written after failed rename, but got:", err)
+		}
+
+		// Without directory the file can't be opened again.
+		err = os.RemoveAll(dir)
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		_, err = f.Write([]byte("third\n"))
+		if err == nil {
+			t.Fatal("Expected error when the file can't be opened again")
+		}
+
+		err = os.Mkdir(dir, 0755)
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		_, err = f.Write([]byte("fourth\n"))
+		if err != nil {
+			t.Fatal("Expected opening the file to be retried, but got:", err)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		if string(content) != "fourth\n" {
+			t.Fatal("Expected message to be written after the file was opened again, but got:", string(content))
+		}
+
+		err = app.Stop()
+		if err == nil {
+			t.Fatal("Expected failed rename to be reported when application stops")
+		}
+	})
+}
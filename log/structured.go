@@ -0,0 +1,80 @@
+package log
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Structured returns l as StructuredLogger, if l doesn't implement it, it is adapted:
+// Debug and Warn messages are logged with Info and Error, fields are appended to message as key=value.
+// Level of adapted logger is not shared with l.
+func Structured(l Logger) StructuredLogger {
+	if sl, ok := l.(StructuredLogger); ok {
+		return sl
+	}
+
+	level := int32(LevelDebug)
+	return &structuredAdapter{logger: l, level: &level}
+}
+
+type structuredAdapter struct {
+	logger Logger
+	fields []Field
+	level  *int32
+}
+
+func (sa *structuredAdapter) Debug(a ...interface{}) { sa.Log(LevelDebug, a...) }
+func (sa *structuredAdapter) Info(a ...interface{})  { sa.Log(LevelInfo, a...) }
+func (sa *structuredAdapter) Warn(a ...interface{})  { sa.Log(LevelWarn, a...) }
+func (sa *structuredAdapter) Error(a ...interface{}) { sa.Log(LevelError, a...) }
+
+func (sa *structuredAdapter) Fatal(a ...interface{}) {
+	sa.Log(LevelFatal, a...)
+	exit(1)
+}
+
+func (sa *structuredAdapter) Log(level Level, a ...interface{}) {
+	if level < sa.GetLevel() {
+		return
+	}
+
+	args := make([]interface{}, 0, len(a))
+	fields := append(make([]Field, 0, len(sa.fields)), sa.fields...)
+	for _, v := range a {
+		if field, ok := v.(Field); ok {
+			fields = append(fields, field)
+			continue
+		}
+		args = append(args, v)
+	}
+
+	for _, field := range fields {
+		args = append(args, fmt.Sprintf("%s=%v", field.Key, field.Value))
+	}
+
+	if level >= LevelWarn {
+		sa.logger.Error(args...)
+		return
+	}
+	sa.logger.Info(args...)
+}
+
+func (sa *structuredAdapter) Child(prefix string) Logger {
+	return &structuredAdapter{logger: sa.logger.Child(prefix), fields: sa.fields, level: sa.level}
+}
+
+func (sa *structuredAdapter) With(fields ...Field) StructuredLogger {
+	return &structuredAdapter{
+		logger: sa.logger,
+		fields: append(append(make([]Field, 0, len(sa.fields)+len(fields)), sa.fields...), fields...),
+		level:  sa.level,
+	}
+}
+
+func (sa *structuredAdapter) SetLevel(level Level) {
+	atomic.StoreInt32(sa.level, int32(level))
+}
+
+func (sa *structuredAdapter) GetLevel() Level {
+	return Level(atomic.LoadInt32(sa.level))
+}
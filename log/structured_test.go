@@ -0,0 +1,47 @@
+package log
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+// plainLogger hides StructuredLogger methods of logger, so it is adapted by Structured.
+type plainLogger struct {
+	Logger
+}
+
+func TestStructured(t *testing.T) {
+	t.Run("level", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := Structured(plainLogger{New(buf, "")})
+		child := logger.With(String("key", "value"))
+
+		logger.SetLevel(LevelWarn)
+		child.Info("message")
+		if buf.Len() != 0 {
+			t.Fatal("Expected messages below minimum level not to be logged, but got:", stripColors(buf.String()))
+		}
+
+		child.Warn("message")
+		if buf.Len() == 0 {
+			t.Fatal("Expected messages above minimum level to be logged")
+		}
+	})
+
+	t.Run("concurrent level", func(t *testing.T) {
+		logger := Structured(plainLogger{New(&bytes.Buffer{}, "")})
+
+		wg := sync.WaitGroup{}
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				logger.SetLevel(LevelError)
+				logger.Info("message")
+				logger.GetLevel()
+			}()
+		}
+		wg.Wait()
+	})
+}
@@ -1,10 +1,20 @@
 package telemetry
 
-import "github.com/corioders/gokit/application"
+import (
+	"context"
+	"time"
+
+	"github.com/corioders/gokit/application"
+	"github.com/corioders/gokit/errors"
+)
 
 type SetupTelemetryOptions struct {
 	ServiceName string
 
+	// FlushTimeout is maximum time that flushing exporters can take while application is stopping,
+	// zero means that flushing is bounded only by application's stop deadline.
+	FlushTimeout time.Duration
+
 	TraceExporter *TraceExporterOptions
 }
 
@@ -21,12 +31,12 @@ func StartTelemetry(sr application.StopRegistrar, options *SetupTelemetryOptions
 	switch options.TraceExporter.ExporeterType {
 	case TraceExpoterJaeger:
 		flush, err := StartJaegerTracing(options.ServiceName, options.TraceExporter.CollectorEndpoint)
-		sr.RegisterOnStop("Flush jaeger tracing exporter", func() error {
+		registerErr := sr.RegisterOnStopContext("Flush jaeger tracing exporter", func(ctx context.Context) error {
 			flush()
 			return nil
-		})
+		}, application.WithStopTimeout(options.FlushTimeout))
 
-		if err != nil {
+		if err := errors.Append(err, registerErr); err != nil {
 			return err
 		}
 	}
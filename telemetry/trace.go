@@ -1,8 +1,7 @@
 package telemetry
 
 import (
-	"errors"
-
+	"github.com/corioders/gokit/errors"
 	"go.opentelemetry.io/otel/exporters/trace/jaeger"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
@@ -14,14 +13,20 @@ type TraceExporterOptions struct {
 	ExporeterType     traceExporterType
 }
 
+var (
+	ErrTraceExporterTypeNotProvided      = errors.New("Trace exporter type not provided")
+	ErrTraceCollectorEndpointNotProvided = errors.New("Trace collector endpoint not provided")
+)
+
 func (o *TraceExporterOptions) validate() error {
+	var err error
 	if o.ExporeterType == 0 {
-		return errors.New("Trace exporter type not provided")
+		err = errors.Append(err, errors.WithStack(ErrTraceExporterTypeNotProvided))
 	}
 	if o.CollectorEndpoint == "" {
-		return errors.New("Trace collector endpoint not provided")
+		err = errors.Append(err, errors.WithStack(ErrTraceCollectorEndpointNotProvided))
 	}
-	return nil
+	return err
 }
 
 type traceExporterType int
@@ -0,0 +1,301 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/corioders/gokit/errors"
+)
+
+// Codec decodes request bodies and encodes response bodies of one media type.
+// Codecs of other formats, for example msgpack, can be added with RegisterCodec.
+type Codec interface {
+	// Decode decodes data into v, if allowUnknownFields is false, fields that are not present in v must be rejected.
+	Decode(data io.Reader, v interface{}, allowUnknownFields bool) error
+	Encode(w io.Writer, v interface{}) error
+}
+
+// Media types of codecs registered by default.
+const (
+	MediaTypeJSON = "application/json"
+	MediaTypeForm = "application/x-www-form-urlencoded"
+)
+
+var (
+	ErrUnsupportedFormValue = errors.New("Unsupported form value")
+	ErrUnknownFormField     = errors.New("Unknown form field")
+	ErrMultipleJSONValues   = errors.New("Body must contain single JSON value")
+)
+
+type codecRegistry struct {
+	mu         sync.RWMutex
+	codecs     map[string]Codec
+	mediaTypes []string
+}
+
+var codecs = &codecRegistry{
+	codecs: map[string]Codec{
+		MediaTypeJSON: jsonCodec{},
+		MediaTypeForm: formCodec{},
+	},
+	mediaTypes: []string{MediaTypeJSON, MediaTypeForm},
+}
+
+// RegisterCodec registers codec used by Decode and Respond for mediaType, for example "application/msgpack",
+// codec registered for the same media type before is replaced. Respond prefers codecs in order of registration
+// when Accept header allows more of them, json is always the first.
+func RegisterCodec(mediaType string, codec Codec) {
+	mediaType = strings.ToLower(mediaType)
+
+	codecs.mu.Lock()
+	defer codecs.mu.Unlock()
+
+	if _, ok := codecs.codecs[mediaType]; !ok {
+		codecs.mediaTypes = append(codecs.mediaTypes, mediaType)
+	}
+	codecs.codecs[mediaType] = codec
+}
+
+func (cr *codecRegistry) get(mediaType string) (Codec, bool) {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	codec, ok := cr.codecs[mediaType]
+	return codec, ok
+}
+
+func (cr *codecRegistry) list() []string {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	return append([]string(nil), cr.mediaTypes...)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Decode(data io.Reader, v interface{}, allowUnknownFields bool) error {
+	decoder := json.NewDecoder(data)
+	if !allowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	err := decoder.Decode(v)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if decoder.More() {
+		return errors.WithStack(ErrMultipleJSONValues)
+	}
+	return nil
+}
+
+func (jsonCodec) Encode(w io.Writer, v interface{}) error {
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// formCodec decodes form into url.Values, map[string]string or struct,
+// fields of struct are named by form tag, or by their name if they have no tag, "-" tag skips the field.
+type formCodec struct{}
+
+func (formCodec) Decode(data io.Reader, v interface{}, allowUnknownFields bool) error {
+	body, err := ioutil.ReadAll(data)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	switch v := v.(type) {
+	case *url.Values:
+		*v = values
+		return nil
+	case *map[string][]string:
+		*v = values
+		return nil
+	case *map[string]string:
+		*v = make(map[string]string, len(values))
+		for key := range values {
+			(*v)[key] = values.Get(key)
+		}
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return errors.WithMessage(ErrUnsupportedFormValue, fmt.Sprintf("Cannot decode form into %T", v))
+	}
+
+	rv = rv.Elem()
+	fields := formFields(rv.Type())
+	for key, keyValues := range values {
+		index, ok := fields[key]
+		if !ok {
+			if allowUnknownFields {
+				continue
+			}
+			return errors.WithMessage(ErrUnknownFormField, fmt.Sprintf("Unknown form field %s", key))
+		}
+
+		err := setFormValue(rv.Field(index), keyValues)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("Form field %s", key))
+		}
+	}
+	return nil
+}
+
+func (formCodec) Encode(w io.Writer, v interface{}) error {
+	values := url.Values{}
+	switch v := v.(type) {
+	case url.Values:
+		values = v
+	case map[string][]string:
+		values = v
+	case map[string]string:
+		for key, value := range v {
+			values.Set(key, value)
+		}
+	default:
+		rv := reflect.Indirect(reflect.ValueOf(v))
+		if rv.Kind() != reflect.Struct {
+			return errors.WithMessage(ErrUnsupportedFormValue, fmt.Sprintf("Cannot encode %T as form", v))
+		}
+
+		for key, index := range formFields(rv.Type()) {
+			fieldValues, err := formValue(rv.Field(index))
+			if err != nil {
+				return err
+			}
+			values[key] = fieldValues
+		}
+	}
+
+	_, err := io.WriteString(w, values.Encode())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// formFields returns indexes of exported fields of struct type t by their form names.
+func formFields(t reflect.Type) map[string]int {
+	fields := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("form"); ok {
+			name = strings.Split(tag, ",")[0]
+		}
+		if name == "-" {
+			continue
+		}
+		fields[name] = i
+	}
+	return fields
+}
+
+func setFormValue(v reflect.Value, values []string) error {
+	if v.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			err := setFormScalar(slice.Index(i), value)
+			if err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+
+	return setFormScalar(v, values[len(values)-1])
+}
+
+func setFormScalar(v reflect.Value, value string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		v.SetFloat(f)
+	default:
+		return errors.WithMessage(ErrUnsupportedFormValue, fmt.Sprintf("Unsupported type %s", v.Type()))
+	}
+	return nil
+}
+
+func formValue(v reflect.Value) ([]string, error) {
+	if v.Kind() == reflect.Slice {
+		values := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			value, err := formScalar(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	}
+
+	value, err := formScalar(v)
+	if err != nil {
+		return nil, err
+	}
+	return []string{value}, nil
+}
+
+func formScalar(v reflect.Value) (string, error) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	default:
+		return "", errors.WithMessage(ErrUnsupportedFormValue, fmt.Sprintf("Unsupported type %s", v.Type()))
+	}
+}
@@ -0,0 +1,121 @@
+package web
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/corioders/gokit/errors"
+)
+
+// DefaultMaxBodySize is the maximum size of body decoded by Decode, if no WithMaxBodySize option is provided.
+const DefaultMaxBodySize = 1 << 20
+
+var (
+	ErrEmptyBody            = errors.New("Request body is empty")
+	ErrBodyTooLarge         = errors.New("Request body is too large")
+	ErrUnsupportedMediaType = errors.New("Unsupported media type")
+	ErrInvalidBody          = errors.New("Invalid request body")
+)
+
+type decodeOptions struct {
+	maxBodySize        int64
+	allowUnknownFields bool
+}
+
+// DecodeOption configures Decode.
+type DecodeOption func(o *decodeOptions)
+
+// WithMaxBodySize sets maximum size of decoded body in bytes, negative size means no limit.
+func WithMaxBodySize(size int64) DecodeOption {
+	return func(o *decodeOptions) {
+		o.maxBodySize = size
+	}
+}
+
+// AllowUnknownFields disables rejecting bodies with fields that are not present in decoded value.
+func AllowUnknownFields() DecodeOption {
+	return func(o *decodeOptions) {
+		o.allowUnknownFields = true
+	}
+}
+
+// Decode decodes body of r into v with codec chosen by Content-Type header, json is used when header is not set.
+// Errors are classified as errors.KindInvalidArgument, so they are turned into bad request responses,
+// except ErrBodyTooLarge and ErrUnsupportedMediaType classified as errors.KindTooLarge and errors.KindUnsupportedMediaType.
+func Decode(r *http.Request, v interface{}, options ...DecodeOption) error {
+	o := &decodeOptions{
+		maxBodySize: DefaultMaxBodySize,
+	}
+	for _, option := range options {
+		option(o)
+	}
+
+	mediaType := MediaTypeJSON
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		var err error
+		mediaType, _, err = mime.ParseMediaType(contentType)
+		if err != nil {
+			return unsupportedMediaType(errors.WithMessage(ErrUnsupportedMediaType, fmt.Sprintf("Invalid Content-Type %s", contentType)))
+		}
+	}
+
+	codec, ok := codecs.get(strings.ToLower(mediaType))
+	if !ok {
+		return unsupportedMediaType(errors.WithMessage(ErrUnsupportedMediaType, fmt.Sprintf("Unsupported media type %s", mediaType)))
+	}
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return invalidArgument(errors.WithStack(ErrEmptyBody))
+	}
+
+	var body io.Reader = r.Body
+	if o.maxBodySize >= 0 {
+		body = &limitedReader{r: r.Body, n: o.maxBodySize}
+	}
+
+	err := codec.Decode(body, v, o.allowUnknownFields)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrBodyTooLarge):
+		return errors.WithKind(errors.WithMessage(ErrBodyTooLarge, fmt.Sprintf("Request body is larger than %d bytes", o.maxBodySize)), errors.KindTooLarge)
+	case errors.Is(err, io.EOF):
+		return invalidArgument(errors.WithStack(ErrEmptyBody))
+	default:
+		return invalidArgument(errors.WithMessage(ErrInvalidBody, fmt.Sprintf("Invalid request body: %v", errors.GetErrorNoStack(err))))
+	}
+}
+
+func invalidArgument(err error) error {
+	return errors.WithKind(err, errors.KindInvalidArgument)
+}
+
+func unsupportedMediaType(err error) error {
+	return errors.WithKind(err, errors.KindUnsupportedMediaType)
+}
+
+// limitedReader returns ErrBodyTooLarge when more than n bytes are read.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	if lr.n < 0 {
+		return 0, ErrBodyTooLarge
+	}
+
+	// Read one byte more than allowed to detect that body is too large.
+	if int64(len(p)) > lr.n+1 {
+		p = p[:lr.n+1]
+	}
+	n, err := lr.r.Read(p)
+	lr.n -= int64(n)
+	if lr.n < 0 {
+		return n, ErrBodyTooLarge
+	}
+	return n, err
+}
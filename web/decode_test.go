@@ -0,0 +1,148 @@
+package web
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/corioders/gokit/errors"
+)
+
+type testBody struct {
+	Name  string   `json:"name" form:"name"`
+	Count int      `json:"count" form:"count"`
+	Tags  []string `json:"tags" form:"tag"`
+}
+
+func newDecodeRequest(contentType string, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	return r
+}
+
+// testCodec decodes whole body as string, it stands for codecs like msgpack registered by applications.
+type testCodec struct{}
+
+func (testCodec) Decode(data io.Reader, v interface{}, allowUnknownFields bool) error {
+	body, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	if string(body) == "invalid" {
+		return fmt.Errorf("Invalid test body")
+	}
+	*(v.(*string)) = string(body)
+	return nil
+}
+
+func (testCodec) Encode(w io.Writer, v interface{}) error {
+	_, err := io.WriteString(w, v.(string))
+	return err
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		var body testBody
+		err := Decode(newDecodeRequest("application/json; charset=utf-8", `{"name":"a","count":2,"tags":["x"]}`), &body)
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		if body.Name != "a" || body.Count != 2 || len(body.Tags) != 1 {
+			t.Fatal("Expected body to be decoded, but got:", body)
+		}
+	})
+
+	t.Run("json without content type", func(t *testing.T) {
+		var body testBody
+		err := Decode(newDecodeRequest("", `{"name":"a"}`), &body)
+		if err != nil || body.Name != "a" {
+			t.Fatal("Expected body to be decoded as json, but got:", body, err)
+		}
+	})
+
+	t.Run("unknown fields", func(t *testing.T) {
+		var body testBody
+		err := Decode(newDecodeRequest("application/json", `{"name":"a","other":1}`), &body)
+		if !errors.Is(err, ErrInvalidBody) || errors.KindOf(err) != errors.KindInvalidArgument {
+			t.Fatal("Expected ErrInvalidBody classified as invalid argument, but got:", err)
+		}
+
+		err = Decode(newDecodeRequest("application/json", `{"name":"a","other":1}`), &body, AllowUnknownFields())
+		if err != nil {
+			t.Fatal("Expected unknown fields to be allowed, but got:", err)
+		}
+	})
+
+	t.Run("multiple values", func(t *testing.T) {
+		var body testBody
+		err := Decode(newDecodeRequest("application/json", `{"name":"a"}{"name":"b"}`), &body)
+		if !errors.Is(err, ErrInvalidBody) {
+			t.Fatal("Expected ErrInvalidBody, but got:", err)
+		}
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		var body testBody
+		err := Decode(newDecodeRequest("application/json", ""), &body)
+		if !errors.Is(err, ErrEmptyBody) {
+			t.Fatal("Expected ErrEmptyBody, but got:", err)
+		}
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		var body testBody
+		err := Decode(newDecodeRequest("application/json", `{"name":"abcdefghijklmnopqrstuvwxyz"}`), &body, WithMaxBodySize(10))
+		if !errors.Is(err, ErrBodyTooLarge) || errors.HTTPStatus(err) != http.StatusRequestEntityTooLarge {
+			t.Fatal("Expected ErrBodyTooLarge, but got:", err)
+		}
+	})
+
+	t.Run("unsupported media type", func(t *testing.T) {
+		var body testBody
+		err := Decode(newDecodeRequest("text/plain", "name"), &body)
+		if !errors.Is(err, ErrUnsupportedMediaType) || errors.HTTPStatus(err) != http.StatusUnsupportedMediaType {
+			t.Fatal("Expected ErrUnsupportedMediaType, but got:", err)
+		}
+	})
+
+	t.Run("form", func(t *testing.T) {
+		var body testBody
+		err := Decode(newDecodeRequest(MediaTypeForm, "name=a&count=2&tag=x&tag=y"), &body)
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		if body.Name != "a" || body.Count != 2 || strings.Join(body.Tags, ",") != "x,y" {
+			t.Fatal("Expected form to be decoded, but got:", body)
+		}
+
+		err = Decode(newDecodeRequest(MediaTypeForm, "name=a&other=1"), &body)
+		if !errors.Is(err, ErrInvalidBody) {
+			t.Fatal("Expected unknown form field to be rejected, but got:", err)
+		}
+
+		err = Decode(newDecodeRequest(MediaTypeForm, "count=abc"), &body)
+		if !errors.Is(err, ErrInvalidBody) {
+			t.Fatal("Expected invalid form value to be rejected, but got:", err)
+		}
+	})
+
+	t.Run("registered codec", func(t *testing.T) {
+		RegisterCodec("application/x-test", testCodec{})
+
+		var body string
+		err := Decode(newDecodeRequest("application/x-test", "content"), &body)
+		if err != nil || body != "content" {
+			t.Fatal("Expected body to be decoded with registered codec, but got:", body, err)
+		}
+
+		err = Decode(newDecodeRequest("application/x-test", "invalid"), &body)
+		if !errors.Is(err, ErrInvalidBody) || !strings.Contains(errors.GetErrorNoStack(err), "Invalid test body") {
+			t.Fatal("Expected error of registered codec to be included in message, but got:", err)
+		}
+	})
+}
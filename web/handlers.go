@@ -0,0 +1,63 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/corioders/gokit/errors"
+	"github.com/corioders/gokit/log"
+)
+
+// ErrorHandler handles error returned by Handler, it decides response if handler hasn't written it yet,
+// that can be checked with Written.
+type ErrorHandler func(ctx context.Context, rw http.ResponseWriter, r *http.Request, err error)
+
+// DefaultErrorHandler returns ErrorHandler that logs err with logger and,
+// if response wasn't written, responds with status code of err's kind.
+func DefaultErrorHandler(logger log.Logger) ErrorHandler {
+	return func(ctx context.Context, rw http.ResponseWriter, r *http.Request, err error) {
+		log.WithContext(ctx, logger).Error(fmt.Sprintf("ERROR IN GOKIT WEB: %v", err))
+
+		if !Written(rw) {
+			statusCode := errors.HTTPStatus(err)
+			http.Error(rw, http.StatusText(statusCode), statusCode)
+		}
+	}
+}
+
+// statusHandler returns handler that responds with statusCode.
+func statusHandler(statusCode int) Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+		http.Error(rw, http.StatusText(statusCode), statusCode)
+		return nil
+	}
+}
+
+type ctxKey int
+
+const (
+	ctxKeyPanic ctxKey = iota
+	ctxKeyAccept
+)
+
+// PanicError returns panic recovered by router as error, it is available in ctx of router's Panic handler.
+func PanicError(ctx context.Context) error {
+	err, _ := ctx.Value(ctxKeyPanic).(error)
+	return err
+}
+
+// newPanicHandler returns httptreemux panic handler that calls handler with recovered panic in ctx.
+func newPanicHandler(handler Handler, errorHandler ErrorHandler, middleware []Middleware) func(rw http.ResponseWriter, r *http.Request, recovered interface{}) {
+	h := newHTTPHandler(handler, errorHandler, middleware, nil)
+
+	return func(rw http.ResponseWriter, r *http.Request, recovered interface{}) {
+		// ErrAbortHandler is used to abort response, it must reach http.Server.
+		if recovered == http.ErrAbortHandler {
+			panic(recovered)
+		}
+
+		ctx := context.WithValue(r.Context(), ctxKeyPanic, errors.FromPanic(recovered))
+		h(rw, r.WithContext(ctx))
+	}
+}
@@ -0,0 +1,60 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/corioders/gokit/application"
+	"github.com/corioders/gokit/errors"
+	"github.com/corioders/gokit/web"
+)
+
+const (
+	HealthPath    = "/healthz"
+	ReadinessPath = "/readyz"
+)
+
+// Register registers health handler on HealthPath and readiness handler on ReadinessPath.
+func Register(rg web.RouterGroup, a *application.Application, middleware ...web.Middleware) {
+	rg.Handle(http.MethodGet, HealthPath, NewHealthHandler(a), middleware...)
+	rg.Handle(http.MethodGet, ReadinessPath, NewReadinessHandler(a), middleware...)
+}
+
+// NewHealthHandler creates handler that renders application's health report as json.
+func NewHealthHandler(a *application.Application) web.Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+		return writeReport(rw, a.Health(ctx))
+	}
+}
+
+// NewReadinessHandler creates handler that renders application's readiness report as json,
+// it starts failing once application starts stopping.
+func NewReadinessHandler(a *application.Application) web.Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+		return writeReport(rw, a.Readiness(ctx))
+	}
+}
+
+func writeReport(rw http.ResponseWriter, report *application.HealthReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	status := http.StatusOK
+	if report.Status == application.HealthStatusFailing {
+		status = http.StatusServiceUnavailable
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Header().Set("Cache-Control", "no-store")
+	rw.WriteHeader(status)
+
+	_, err = rw.Write(data)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
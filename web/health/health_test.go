@@ -0,0 +1,111 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/corioders/gokit/application"
+	"github.com/corioders/gokit/log"
+	"github.com/corioders/gokit/web"
+)
+
+func serve(t *testing.T, a *application.Application, path string) (*httptest.ResponseRecorder, *application.HealthReport) {
+	logger := log.New(io.Discard, "")
+	router := web.NewRouter(logger, nil)
+	Register(router, a)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+
+	report := &application.HealthReport{}
+	err := json.Unmarshal(rr.Body.Bytes(), report)
+	if err != nil {
+		t.Fatal("Expected response body to be health report, but got:", rr.Body.String())
+	}
+	return rr, report
+}
+
+func TestHealth(t *testing.T) {
+	t.Run("healthy", func(t *testing.T) {
+		a := application.New(log.New(io.Discard, ""))
+		a.RegisterHealthCheck("db", func(ctx context.Context) error { return nil }, application.Critical())
+
+		rr, report := serve(t, a, HealthPath)
+		if rr.Code != http.StatusOK || report.Status != application.HealthStatusOK {
+			t.Fatal("Expected ok status, but got:", rr.Code, rr.Body.String())
+		}
+		if rr.Header().Get("Content-Type") != "application/json" || rr.Header().Get("Cache-Control") != "no-store" {
+			t.Fatal("Expected json response that is not cached, but got:", rr.Header())
+		}
+		if len(report.Checks) != 1 || report.Checks[0].Name != "db" || report.Checks[0].Status != application.HealthStatusOK || report.Checks[0].Error != "" {
+			t.Fatal("Expected result of each check in response body, but got:", rr.Body.String())
+		}
+	})
+
+	t.Run("unhealthy", func(t *testing.T) {
+		a := application.New(log.New(io.Discard, ""))
+		a.RegisterHealthCheck("db", func(ctx context.Context) error { return fmt.Errorf("connection refused") }, application.Critical())
+		a.RegisterHealthCheck("cache", func(ctx context.Context) error { return nil })
+
+		rr, report := serve(t, a, HealthPath)
+		if rr.Code != http.StatusServiceUnavailable || report.Status != application.HealthStatusFailing {
+			t.Fatal("Expected failing status when critical check fails, but got:", rr.Code, rr.Body.String())
+		}
+		if report.Checks[0].Status != application.HealthStatusFailing || report.Checks[0].Error != "connection refused" {
+			t.Fatal("Expected failing check with its error message, but got:", rr.Body.String())
+		}
+	})
+
+	t.Run("degraded", func(t *testing.T) {
+		a := application.New(log.New(io.Discard, ""))
+		a.RegisterHealthCheck("cache", func(ctx context.Context) error { return fmt.Errorf("connection refused") })
+
+		rr, report := serve(t, a, HealthPath)
+		if rr.Code != http.StatusOK || report.Status != application.HealthStatusDegraded {
+			t.Fatal("Expected degraded status when non-critical check fails, but got:", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		a := application.New(log.New(io.Discard, ""))
+		a.RegisterHealthCheck("db", func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		}, application.Critical(), application.WithHealthCheckTimeout(10*time.Millisecond))
+
+		rr, report := serve(t, a, HealthPath)
+		if rr.Code != http.StatusServiceUnavailable || report.Status != application.HealthStatusFailing {
+			t.Fatal("Expected failing status when check times out, but got:", rr.Code, rr.Body.String())
+		}
+		if !strings.HasPrefix(report.Checks[0].Error, "Health check timed out") || strings.Contains(report.Checks[0].Error, "\n") {
+			t.Fatal("Expected timeout message without stack, but got:", report.Checks[0].Error)
+		}
+	})
+
+	t.Run("readiness while stopping", func(t *testing.T) {
+		a := application.New(log.New(io.Discard, ""))
+		a.RegisterHealthCheck("db", func(ctx context.Context) error { return nil })
+
+		rr, _ := serve(t, a, ReadinessPath)
+		if rr.Code != http.StatusOK {
+			t.Fatal("Expected application to be ready, but got:", rr.Code, rr.Body.String())
+		}
+
+		err := a.Stop()
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		rr, report := serve(t, a, ReadinessPath)
+		if rr.Code != http.StatusServiceUnavailable || !report.Stopping {
+			t.Fatal("Expected application not to be ready while stopping, but got:", rr.Code, rr.Body.String())
+		}
+	})
+}
@@ -74,7 +74,7 @@ func New(name string, key Key) (*Accesscontrol, error) {
 
 	_, ok := accesscontrolNames.Load(name)
 	if ok {
-		return nil, errors.WithMessage(ErrNameNonUnique, fmt.Sprintf(`name "%v" is not unique`, name))
+		return nil, errors.With(errors.WithMessage(ErrNameNonUnique, fmt.Sprintf(`name "%v" is not unique`, name)), "name", name)
 	}
 
 	singerKey := key[:32]
@@ -3,6 +3,7 @@ package accesscontrol
 import (
 	"io"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/corioders/gokit/errors"
@@ -41,6 +42,13 @@ func TestNewAccesscontrol(t *testing.T) {
 		if !errors.Is(err, ErrNameNonUnique) {
 			t.Fatalf("Expected ErrNameNonUnique when accesscontrol name is a duplicate, but got: %v", err)
 		}
+		if !strings.Contains(errors.GetErrorNoStack(err), `name "TestNewAccesscontrol, accesscontrol, name duplicate" is not unique`) {
+			t.Fatalf("Expected error message to contain duplicate name, but got: %v", errors.GetErrorNoStack(err))
+		}
+		fields := errors.Fields(err)
+		if len(fields) != 1 || fields[0].Key != "name" {
+			t.Fatalf("Expected error to have name field, but got: %v", fields)
+		}
 	} else {
 		t.Fatal("Expected error when creating new accesscontrol with duplicate name")
 	}
@@ -32,7 +32,7 @@ var (
 func NewManager(name string) (*RoleManager, error) {
 	_, ok := roleManagers.Load(name)
 	if ok {
-		return nil, errors.WithMessage(ErrRoleManagerNonUnique, fmt.Sprintf(`name "%v" is not unique`, name))
+		return nil, errors.With(errors.WithMessage(ErrRoleManagerNonUnique, fmt.Sprintf(`name "%v" is not unique`, name)), "name", name)
 	}
 
 	roleManager := &RoleManager{
@@ -68,7 +68,7 @@ func managerFromName(name string) (*RoleManager, error) {
 func (rm *RoleManager) insertRole(r *Role) error {
 	_, ok := rm.roles.Load(r.ri.name)
 	if ok {
-		return errors.WithMessage(ErrRoleNameNonUnique, fmt.Sprintf(`name "%v" is not unique`, r.ri.name))
+		return errors.With(errors.WithMessage(ErrRoleNameNonUnique, fmt.Sprintf(`name "%v" is not unique`, r.ri.name)), "name", r.ri.name)
 	}
 
 	rm.roles.Store(r.ri.name, r)
@@ -87,7 +87,7 @@ func (rm *RoleManager) getRole(name string) (*Role, bool) {
 func (rm *RoleManager) insertPermission(p *Permission) error {
 	_, ok := rm.permissions.Load(p.name)
 	if ok {
-		return errors.WithMessage(ErrPermissionNameNonUnique, fmt.Sprintf(`name "%v" is not unique`, p.name))
+		return errors.With(errors.WithMessage(ErrPermissionNameNonUnique, fmt.Sprintf(`name "%v" is not unique`, p.name)), "name", p.name)
 	}
 
 	rm.permissions.Store(p.name, p)
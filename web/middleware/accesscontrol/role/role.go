@@ -50,7 +50,7 @@ func (r *Role) UnmarshalJSON(data []byte) error {
 
 	role, ok := manager.getRole(rrj.Name)
 	if !ok {
-		return errors.WithMessage(ErrRoleNotExists, fmt.Sprintf(`role name: "%v"`, rrj.Name))
+		return errors.With(errors.WithMessage(ErrRoleNotExists, fmt.Sprintf(`role name: "%v"`, rrj.Name)), "role", rrj.Name)
 	}
 
 	r.ri = role.ri
@@ -55,6 +55,11 @@ func (crw *compressionResponseWriter) Write(b []byte) (int, error) {
 	return crw.compressionWriter.Write(b)
 }
 
+// Unwrap returns underlying ResponseWriter.
+func (crw *compressionResponseWriter) Unwrap() http.ResponseWriter {
+	return crw.ResponseWriter
+}
+
 func Compression() web.Middleware {
 	return func(handler web.Handler) web.Handler {
 		return func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
@@ -68,6 +73,9 @@ func Compression() web.Middleware {
 				return handler(ctx, rw, r)
 			}
 
+			// Response depends on Accept-Encoding, caches must not share it between different encodings.
+			rw.Header().Add("Vary", "Accept-Encoding")
+
 			usingCompression := false
 			var compressionWriter compressor
 			compressions := strings.Split(r.Header.Get("Accept-Encoding"), ",")
@@ -101,9 +109,22 @@ func Compression() web.Middleware {
 			}
 
 			crw := compressionResponseWriter{compressionWriter: compressionWriter, ResponseWriter: rw}
+			returned := false
+			defer func() {
+				if !returned {
+					// Handler panicked, response to the panic could be written without compression.
+					crw.compressionWriter.Reset(nil)
+					rw.Header().Del("Content-Encoding")
+				}
+				putCompressor(crw.compressionWriter)
+			}()
+
 			err := handler(ctx, &crw, r)
+			returned = true
 			if err != nil {
 				crw.compressionWriter.Reset(nil)
+				// Response to the error could be written without compression.
+				rw.Header().Del("Content-Encoding")
 				// Don't mess with error returned by handler.
 				return err
 			}
@@ -117,15 +138,18 @@ func Compression() web.Middleware {
 				crw.compressionWriter.Reset(nil)
 			}
 
-			switch w := crw.compressionWriter.(type) {
-			case *gzip.Writer:
-				gzipPool.Put(w)
-
-			case *flate.Writer:
-				flatePool.Put(w)
-			}
-
 			return nil
 		}
 	}
 }
+
+// putCompressor returns w to its pool.
+func putCompressor(w compressor) {
+	switch w := w.(type) {
+	case *gzip.Writer:
+		gzipPool.Put(w)
+
+	case *flate.Writer:
+		flatePool.Put(w)
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corioders/gokit/log"
+	"github.com/corioders/gokit/web"
+)
+
+func TestCompression(t *testing.T) {
+	logger := log.New(io.Discard, "")
+
+	t.Run("respond", func(t *testing.T) {
+		router := web.NewRouter(logger, nil, Compression())
+		router.Handle(http.MethodGet, "/", func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+			return web.Respond(ctx, rw, http.StatusOK, map[string]string{"message": "compressed"})
+		})
+
+		rr := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept-Encoding", "gzip")
+		router.ServeHTTP(rr, r)
+
+		if rr.Header().Get("Content-Encoding") != "gzip" || rr.Header().Get("Content-Length") != "" {
+			t.Fatal("Expected compressed response without Content-Length of uncompressed body, but got:", rr.Header())
+		}
+		if rr.Header().Values("Vary")[0] != "Accept-Encoding" {
+			t.Fatal("Expected Vary header with Accept-Encoding, but got:", rr.Header())
+		}
+
+		gr, err := gzip.NewReader(rr.Body)
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		body, err := io.ReadAll(gr)
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		if string(body) != "{\"message\":\"compressed\"}\n" {
+			t.Fatal("Expected compressed json body, but got:", string(body))
+		}
+	})
+	t.Run("panic", func(t *testing.T) {
+		router := web.NewRouter(logger, nil, Errors(logger), Compression())
+		router.Handle(http.MethodGet, "/", func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+			panic("test panic")
+		})
+
+		rr := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept-Encoding", "gzip")
+		router.ServeHTTP(rr, r)
+
+		if rr.Code != http.StatusInternalServerError || rr.Header().Get("Content-Encoding") != "" {
+			t.Fatal("Expected uncompressed response to the panic, but got:", rr.Code, rr.Header())
+		}
+		if !json.Valid(rr.Body.Bytes()) {
+			t.Fatal("Expected problem details to be valid json, but got:", rr.Body.String())
+		}
+	})
+}
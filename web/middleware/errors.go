@@ -2,38 +2,160 @@ package middleware
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 
+	"github.com/corioders/gokit/constant"
 	"github.com/corioders/gokit/errors"
 	"github.com/corioders/gokit/log"
+	"github.com/corioders/gokit/math/rand"
 	"github.com/corioders/gokit/web"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// problemDetails is error response described by RFC 7807.
+type problemDetails struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Instance string `json:"instance,omitempty"`
+	TraceID  string `json:"traceId,omitempty"`
+
+	// Internal details, they are included only outside of production.
+	Detail string `json:"detail,omitempty"`
+	Stack  string `json:"stack,omitempty"`
+}
+
+var traceIDRand = rand.NewCrypto()
+
+// PanicReporter is called with every panic recovered by Errors middleware,
+// err has stack of the place where panic occurred.
+type PanicReporter func(ctx context.Context, r *http.Request, err error)
+
+type errorsOptions struct {
+	panicReporter PanicReporter
+}
+
+// ErrorsOption configures Errors middleware.
+type ErrorsOption func(o *errorsOptions)
+
+// WithPanicReporter sets PanicReporter, that can be used to send panics to external service.
+func WithPanicReporter(reporter PanicReporter) ErrorsOption {
+	return func(o *errorsOptions) {
+		o.panicReporter = reporter
+	}
+}
+
 // Errors middelware catches errors and recovers from panics.
-func Errors(logger log.Logger) web.Middleware {
+// Caught errors are logged and written as application/problem+json response with status derived from error's kind,
+// unless handler already started writing response. Recovered panics are reported with status 500,
+// except http.ErrAbortHandler which is panicked again, so net/http can abort the response.
+func Errors(logger log.Logger, options ...ErrorsOption) web.Middleware {
+	o := &errorsOptions{}
+	for _, option := range options {
+		option(o)
+	}
+
 	logger = logger.Child("Errors middleware")
 	return func(handler web.Handler) web.Handler {
 		return func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
 			defer func() {
-				r := recover()
-
-				if r != nil {
-					if err, ok := r.(error); ok {
-						logger.Error(errors.WithMessage(err, "Panic"))
-						return
-					}
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
 
-					logger.Error("Panic:", r)
+				err := errors.FromPanic(recovered)
+				if o.panicReporter != nil {
+					o.panicReporter(ctx, r, err)
 				}
+
+				handleError(ctx, logger, rw, r, err)
 			}()
 
 			err := handler(ctx, rw, r)
 
 			if err != nil {
-				logger.Error(errors.WithMessage(err, "Error"))
+				handleError(ctx, logger, rw, r, errors.WithMessage(err, "Error"))
 			}
 
 			return nil
 		}
 	}
 }
+
+func handleError(ctx context.Context, logger log.Logger, rw http.ResponseWriter, r *http.Request, err error) {
+	// Logger bound to ctx carries trace_id of active span, so it is logged as field only when there is no span.
+	// Request details are logged as fields instead of being attached to err, so its stack isn't extended.
+	logger = log.WithContext(ctx, logger)
+	traceID := getTraceID(ctx, r)
+	args := []interface{}{err, log.String("method", r.Method), log.String("path", r.URL.Path)}
+	if traceID != "" && !trace.SpanContextFromContext(ctx).IsValid() {
+		args = append(args, log.String("trace_id", traceID))
+	}
+	logger.Error(args...)
+
+	if web.Written(rw) {
+		return
+	}
+
+	err = writeProblemDetails(rw, r, err, traceID)
+	if err != nil {
+		logger.Error(errors.WithMessage(err, "Writing problem details"))
+	}
+}
+
+// getTraceID returns id of trace from ctx, if there is no trace X-Request-Id header is used,
+// if there is also no header random id is generated.
+func getTraceID(ctx context.Context, r *http.Request) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if spanContext.IsValid() {
+		return spanContext.TraceID().String()
+	}
+
+	requestID := r.Header.Get("X-Request-Id")
+	if requestID != "" {
+		return requestID
+	}
+
+	traceID, err := traceIDRand.String(32)
+	if err != nil {
+		return ""
+	}
+	return traceID
+}
+
+func writeProblemDetails(rw http.ResponseWriter, r *http.Request, err error, traceID string) error {
+	statusCode := errors.HTTPStatus(err)
+	pd := problemDetails{
+		Type:     "about:blank",
+		Title:    http.StatusText(statusCode),
+		Status:   statusCode,
+		Instance: r.URL.Path,
+		TraceID:  traceID,
+	}
+
+	if !constant.IsProduction {
+		pd.Detail = errors.GetErrorNoStack(err)
+		pd.Stack = errors.FormatStack(err, &errors.StackFormatOptions{})
+	}
+
+	data, marshalErr := json.Marshal(pd)
+	if marshalErr != nil {
+		return errors.WithStack(marshalErr)
+	}
+
+	rw.Header().Del("Content-Length")
+	rw.Header().Set("Content-Type", "application/problem+json")
+	rw.WriteHeader(statusCode)
+
+	_, writeErr := rw.Write(data)
+	if writeErr != nil {
+		return errors.WithStack(writeErr)
+	}
+
+	return nil
+}
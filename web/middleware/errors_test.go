@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/corioders/gokit/errors"
+	"github.com/corioders/gokit/log"
+	"github.com/corioders/gokit/log/logtest"
+	"github.com/corioders/gokit/web"
+)
+
+func TestErrors(t *testing.T) {
+	logger := log.New(io.Discard, "")
+
+	serve := func(handler web.Handler) *httptest.ResponseRecorder {
+		router := web.NewRouter(logger, nil, Errors(logger))
+		router.Handle(http.MethodGet, "/", handler)
+
+		rr := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-Request-Id", "requestID")
+		router.ServeHTTP(rr, r)
+		return rr
+	}
+
+	t.Run("error", func(t *testing.T) {
+		rr := serve(func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+			return errors.WithKind(errors.New("test error"), errors.KindNotFound)
+		})
+
+		if rr.Code != http.StatusNotFound {
+			t.Fatal("Expected status to be derived from error kind, but got:", rr.Code)
+		}
+		if rr.Header().Get("Content-Type") != "application/problem+json" {
+			t.Fatal("Expected content type to be application/problem+json, but got:", rr.Header().Get("Content-Type"))
+		}
+
+		pd := problemDetails{}
+		err := json.Unmarshal(rr.Body.Bytes(), &pd)
+		if err != nil {
+			t.Fatal("Error while unmarshaling problem details, error:", err)
+		}
+
+		if pd.Status != http.StatusNotFound || pd.TraceID != "requestID" || pd.Instance != "/" {
+			t.Fatal("Expected problem details to contain status, trace id and instance, but got:", pd)
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		rr := serve(func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+			panic("test panic")
+		})
+
+		if rr.Code != http.StatusInternalServerError {
+			t.Fatal("Expected status of recovered panic to be 500, but got:", rr.Code)
+		}
+	})
+
+	t.Run("already written", func(t *testing.T) {
+		rr := serve(func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+			rw.WriteHeader(http.StatusAccepted)
+			return errors.New("test error")
+		})
+
+		if rr.Code != http.StatusAccepted || rr.Body.Len() != 0 {
+			t.Fatal("Expected response written by handler to be preserved, but got:", rr.Code)
+		}
+	})
+}
+
+func TestErrorsPanic(t *testing.T) {
+	logger := log.New(io.Discard, "")
+
+	t.Run("reporter", func(t *testing.T) {
+		var reportedErr error
+		router := web.NewRouter(logger, nil, Errors(logger, WithPanicReporter(func(ctx context.Context, r *http.Request, err error) {
+			reportedErr = err
+		})))
+		router.Handle(http.MethodGet, "/", func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+			panic("test panic")
+		})
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if reportedErr == nil {
+			t.Fatal("Expected panic to be reported")
+		}
+		if frames := errors.Frames(reportedErr); len(frames) == 0 || !strings.Contains(frames[0].Func, "TestErrorsPanic") {
+			t.Fatal("Expected first frame of reported panic to be the function that panicked, but got:", frames)
+		}
+	})
+
+	t.Run("logged", func(t *testing.T) {
+		tl := logtest.New(t)
+		router := web.NewRouter(tl, nil, Errors(tl))
+		router.Handle(http.MethodGet, "/", func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+			panic("test panic")
+		})
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		entries := tl.Filter(func(entry *log.Entry) bool { return len(entry.Errors) != 0 })
+		if len(entries) != 1 {
+			t.Fatal("Expected panic to be logged, but got:", tl.String())
+		}
+		if frames := errors.Frames(entries[0].Errors[0]); len(frames) == 0 || !strings.Contains(frames[0].Func, "TestErrorsPanic") {
+			t.Fatal("Expected first frame of logged panic to be the function that panicked, but got:", frames)
+		}
+
+		fields := map[string]interface{}{}
+		for _, field := range entries[0].Fields {
+			fields[field.Key] = field.Value
+		}
+		if fields["method"] != http.MethodGet || fields["path"] != "/" {
+			t.Fatal("Expected request to be logged as fields, but got:", entries[0].Fields)
+		}
+	})
+
+	t.Run("abort handler", func(t *testing.T) {
+		router := web.NewRouter(logger, nil, Errors(logger))
+		router.Handle(http.MethodGet, "/", func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+			panic(http.ErrAbortHandler)
+		})
+
+		defer func() {
+			if recover() != http.ErrAbortHandler {
+				t.Fatal("Expected http.ErrAbortHandler to be panicked again")
+			}
+		}()
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
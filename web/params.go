@@ -0,0 +1,120 @@
+package web
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/corioders/gokit/errors"
+	"github.com/dimfeld/httptreemux"
+)
+
+var (
+	ErrParamNotFound = errors.New("Path parameter not found")
+	ErrInvalidParam  = errors.New("Invalid path parameter")
+)
+
+// Params returns path parameters of request with ctx, for example id for path /users/:id and rest for path /files/*rest.
+func Params(ctx context.Context) map[string]string {
+	return httptreemux.ContextParams(ctx)
+}
+
+// Param returns path parameter with name or empty string if there is no such parameter.
+func Param(r *http.Request, name string) string {
+	return Params(r.Context())[name]
+}
+
+// ParamInt returns path parameter with name parsed as int,
+// invalid values are classified as errors.KindInvalidArgument,
+// parameter missing from the route is programming error classified as errors.KindInternal.
+func ParamInt(r *http.Request, name string) (int, error) {
+	value, err := param(r, name)
+	if err != nil {
+		return 0, err
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, invalidParam(name, value, "integer")
+	}
+	return i, nil
+}
+
+// ParamUint64 returns path parameter with name parsed as uint64,
+// invalid values are classified as errors.KindInvalidArgument,
+// parameter missing from the route is programming error classified as errors.KindInternal.
+func ParamUint64(r *http.Request, name string) (uint64, error) {
+	value, err := param(r, name)
+	if err != nil {
+		return 0, err
+	}
+
+	u, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, invalidParam(name, value, "unsigned integer")
+	}
+	return u, nil
+}
+
+// UUID is universally unique identifier, it can be converted to UUID types of other packages that are [16]byte.
+type UUID [16]byte
+
+// String returns uuid in canonical form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx.
+func (u UUID) String() string {
+	s := hex.EncodeToString(u[:])
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+// ParamUUID returns path parameter with name parsed as uuid in canonical form,
+// invalid values are classified as errors.KindInvalidArgument,
+// parameter missing from the route is programming error classified as errors.KindInternal.
+func ParamUUID(r *http.Request, name string) (UUID, error) {
+	value, err := param(r, name)
+	if err != nil {
+		return UUID{}, err
+	}
+
+	var u UUID
+	if len(value) != 36 || value[8] != '-' || value[13] != '-' || value[18] != '-' || value[23] != '-' {
+		return UUID{}, invalidParam(name, value, "uuid")
+	}
+	_, err = hex.Decode(u[:], []byte(strings.Replace(value, "-", "", 4)))
+	if err != nil {
+		return UUID{}, invalidParam(name, value, "uuid")
+	}
+	return u, nil
+}
+
+// ParamEnum returns path parameter with name if it is one of values,
+// invalid values are classified as errors.KindInvalidArgument,
+// parameter missing from the route is programming error classified as errors.KindInternal.
+func ParamEnum(r *http.Request, name string, values ...string) (string, error) {
+	value, err := param(r, name)
+	if err != nil {
+		return "", err
+	}
+
+	for _, v := range values {
+		if value == v {
+			return value, nil
+		}
+	}
+	return "", invalidParam(name, value, fmt.Sprintf("one of %s", strings.Join(values, ", ")))
+}
+
+func param(r *http.Request, name string) (string, error) {
+	value, ok := Params(r.Context())[name]
+	if !ok {
+		return "", errors.WithKind(errors.With(ErrParamNotFound, "param", name), errors.KindInternal)
+	}
+	return value, nil
+}
+
+func invalidParam(name string, value string, expected string) error {
+	err := errors.WithMessage(ErrInvalidParam, fmt.Sprintf("Path parameter %s must be %s", name, expected))
+	err = errors.With(errors.With(err, "param", name), "value", value)
+	return errors.WithKind(err, errors.KindInvalidArgument)
+}
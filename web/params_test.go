@@ -0,0 +1,102 @@
+package web
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corioders/gokit/errors"
+	"github.com/corioders/gokit/log"
+)
+
+// serveParams serves request to url with handler registered on path and returns its request.
+func serveParams(t *testing.T, path string, url string) *http.Request {
+	var request *http.Request
+	router := NewRouter(log.New(io.Discard, ""), nil)
+	router.Handle(http.MethodGet, path, func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+		request = r
+		return nil
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, url, nil))
+	if request == nil {
+		t.Fatal("Expected handler to be called")
+	}
+	return request
+}
+
+func TestParams(t *testing.T) {
+	t.Run("params", func(t *testing.T) {
+		r := serveParams(t, "/users/:id/files/*rest", "/users/abc/files/a/b")
+
+		if Param(r, "id") != "abc" || Param(r, "rest") != "a/b" {
+			t.Fatal("Expected path parameters, but got:", Params(r.Context()))
+		}
+	})
+
+	t.Run("int", func(t *testing.T) {
+		r := serveParams(t, "/:valid/:invalid", "/-12/abc")
+
+		i, err := ParamInt(r, "valid")
+		if err != nil || i != -12 {
+			t.Fatal("Expected parsed integer, but got:", i, err)
+		}
+
+		_, err = ParamInt(r, "invalid")
+		if !errors.Is(err, ErrInvalidParam) || errors.KindOf(err) != errors.KindInvalidArgument {
+			t.Fatal("Expected ErrInvalidParam classified as invalid argument, but got:", err)
+		}
+		if errors.HTTPStatus(err) != http.StatusBadRequest {
+			t.Fatal("Expected bad request status, but got:", errors.HTTPStatus(err))
+		}
+
+		_, err = ParamInt(r, "missing")
+		if !errors.Is(err, ErrParamNotFound) || errors.KindOf(err) != errors.KindInternal {
+			t.Fatal("Expected ErrParamNotFound classified as internal, but got:", err)
+		}
+	})
+
+	t.Run("uint64", func(t *testing.T) {
+		r := serveParams(t, "/:valid/:invalid", "/18446744073709551615/-1")
+
+		u, err := ParamUint64(r, "valid")
+		if err != nil || u != 18446744073709551615 {
+			t.Fatal("Expected parsed unsigned integer, but got:", u, err)
+		}
+
+		_, err = ParamUint64(r, "invalid")
+		if !errors.Is(err, ErrInvalidParam) {
+			t.Fatal("Expected ErrInvalidParam, but got:", err)
+		}
+	})
+
+	t.Run("uuid", func(t *testing.T) {
+		r := serveParams(t, "/:valid/:invalid", "/123e4567-e89b-12d3-a456-426614174000/123e4567e89b12d3a456426614174000")
+
+		u, err := ParamUUID(r, "valid")
+		if err != nil || u.String() != "123e4567-e89b-12d3-a456-426614174000" {
+			t.Fatal("Expected parsed uuid, but got:", u, err)
+		}
+
+		_, err = ParamUUID(r, "invalid")
+		if !errors.Is(err, ErrInvalidParam) {
+			t.Fatal("Expected ErrInvalidParam, but got:", err)
+		}
+	})
+
+	t.Run("enum", func(t *testing.T) {
+		r := serveParams(t, "/:valid/:invalid", "/asc/up")
+
+		v, err := ParamEnum(r, "valid", "asc", "desc")
+		if err != nil || v != "asc" {
+			t.Fatal("Expected enum value, but got:", v, err)
+		}
+
+		_, err = ParamEnum(r, "invalid", "asc", "desc")
+		if !errors.Is(err, ErrInvalidParam) {
+			t.Fatal("Expected ErrInvalidParam, but got:", err)
+		}
+	})
+}
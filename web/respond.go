@@ -0,0 +1,114 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/corioders/gokit/errors"
+)
+
+// Respond writes v encoded with codec chosen by Accept header of the request handled by router with ctx,
+// json is used when no registered codec is acceptable. If v is nil or status doesn't allow body, only status is written.
+// Content-Length is not set when response is compressed, so Respond can be used with middleware.Compression.
+func Respond(ctx context.Context, rw http.ResponseWriter, status int, v interface{}) error {
+	if v == nil || !bodyAllowed(status) {
+		rw.WriteHeader(status)
+		return nil
+	}
+
+	mediaType := negotiate(accept(ctx))
+	codec, _ := codecs.get(mediaType)
+
+	buf := &bytes.Buffer{}
+	err := codec.Encode(buf, v)
+	if err != nil {
+		return err
+	}
+
+	header := rw.Header()
+	header.Set("Content-Type", mediaType)
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Add("Vary", "Accept")
+	if header.Get("Content-Encoding") == "" {
+		header.Set("Content-Length", strconv.Itoa(buf.Len()))
+	}
+	rw.WriteHeader(status)
+
+	_, err = rw.Write(buf.Bytes())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func bodyAllowed(status int) bool {
+	return status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified
+}
+
+// accept returns Accept header of the request handled by router with ctx.
+func accept(ctx context.Context) string {
+	accept, _ := ctx.Value(ctxKeyAccept).(string)
+	return accept
+}
+
+type acceptedMediaType struct {
+	mediaType string
+	quality   float64
+}
+
+// negotiate returns registered media type with the highest quality in accept header,
+// media types with the same quality are chosen in order of registration.
+func negotiate(accept string) string {
+	mediaTypes := codecs.list()
+	if accept == "" {
+		return mediaTypes[0]
+	}
+
+	var accepted []acceptedMediaType
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			quality, err = strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+		}
+		accepted = append(accepted, acceptedMediaType{mediaType: mediaType, quality: quality})
+	}
+
+	best, bestQuality := mediaTypes[0], 0.0
+	for _, mediaType := range mediaTypes {
+		quality := mediaTypeQuality(accepted, mediaType)
+		if quality > bestQuality {
+			best, bestQuality = mediaType, quality
+		}
+	}
+	return best
+}
+
+// mediaTypeQuality returns quality of the most specific range in accepted matching mediaType.
+func mediaTypeQuality(accepted []acceptedMediaType, mediaType string) float64 {
+	sorted := append([]acceptedMediaType(nil), accepted...)
+	// More specific ranges take precedence over wildcards.
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return strings.Count(sorted[i].mediaType, "*") < strings.Count(sorted[j].mediaType, "*")
+	})
+
+	mainType := strings.SplitN(mediaType, "/", 2)[0]
+	for _, a := range sorted {
+		if a.mediaType == mediaType || a.mediaType == mainType+"/*" || a.mediaType == "*/*" {
+			return a.quality
+		}
+	}
+	return 0
+}
@@ -0,0 +1,72 @@
+package web
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corioders/gokit/log"
+)
+
+// respond serves request with accept header by handler that responds with v.
+func respond(accept string, status int, v interface{}) *httptest.ResponseRecorder {
+	router := NewRouter(log.New(io.Discard, ""), nil)
+	router.Handle(http.MethodGet, "/", func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+		return Respond(ctx, rw, status, v)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if accept != "" {
+		r.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestRespond(t *testing.T) {
+	body := testBody{Name: "a", Count: 2}
+
+	t.Run("json", func(t *testing.T) {
+		rec := respond("", http.StatusCreated, body)
+		if rec.Code != http.StatusCreated || rec.Body.String() != "{\"name\":\"a\",\"count\":2,\"tags\":null}\n" {
+			t.Fatal("Expected json response, but got:", rec.Code, rec.Body.String())
+		}
+		if rec.Header().Get("Content-Type") != MediaTypeJSON || rec.Header().Get("Content-Length") != "35" {
+			t.Fatal("Expected json headers, but got:", rec.Header())
+		}
+	})
+
+	t.Run("accept", func(t *testing.T) {
+		rec := respond("application/json;q=0.5, application/x-www-form-urlencoded", http.StatusOK, body)
+		if rec.Header().Get("Content-Type") != MediaTypeForm || rec.Body.String() != "count=2&name=a" {
+			t.Fatal("Expected form response preferred by Accept, but got:", rec.Header(), rec.Body.String())
+		}
+		if rec.Header().Get("Vary") != "Accept" {
+			t.Fatal("Expected Vary header, but got:", rec.Header())
+		}
+	})
+
+	t.Run("not acceptable", func(t *testing.T) {
+		rec := respond("text/html", http.StatusOK, body)
+		if rec.Header().Get("Content-Type") != MediaTypeJSON {
+			t.Fatal("Expected json to be used when no codec is acceptable, but got:", rec.Header())
+		}
+	})
+
+	t.Run("wildcard", func(t *testing.T) {
+		rec := respond("text/html, */*;q=0.1", http.StatusOK, body)
+		if rec.Header().Get("Content-Type") != MediaTypeJSON {
+			t.Fatal("Expected the first codec to be used for wildcard, but got:", rec.Header())
+		}
+	})
+
+	t.Run("no content", func(t *testing.T) {
+		rec := respond("", http.StatusNoContent, body)
+		if rec.Code != http.StatusNoContent || rec.Body.Len() != 0 || rec.Header().Get("Content-Type") != "" {
+			t.Fatal("Expected only status to be written, but got:", rec.Code, rec.Header(), rec.Body.String())
+		}
+	})
+}
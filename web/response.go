@@ -0,0 +1,109 @@
+package web
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+
+	"github.com/corioders/gokit/errors"
+)
+
+var (
+	ErrHijackNotSupported = errors.New("ResponseWriter does not support hijacking")
+)
+
+// responseWriter records status of response written by Handler.
+type responseWriter struct {
+	http.ResponseWriter
+
+	statusCode    int
+	headerWritten bool
+}
+
+func newResponseWriter(rw http.ResponseWriter) *responseWriter {
+	return &responseWriter{ResponseWriter: rw}
+}
+
+func (rw *responseWriter) WriteHeader(statusCode int) {
+	if !rw.headerWritten {
+		rw.statusCode = statusCode
+		rw.headerWritten = true
+	}
+
+	rw.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	if !rw.headerWritten {
+		// This is exactly what Go would also do if it hasn't been written yet.
+		rw.statusCode = http.StatusOK
+		rw.headerWritten = true
+	}
+
+	return rw.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher interface, it does nothing if underlying ResponseWriter is not a http.Flusher.
+func (rw *responseWriter) Flush() {
+	flusher, ok := rw.ResponseWriter.(http.Flusher)
+	if !ok {
+		return
+	}
+
+	if !rw.headerWritten {
+		rw.statusCode = http.StatusOK
+		rw.headerWritten = true
+	}
+	flusher.Flush()
+}
+
+// Hijack implements http.Hijacker interface, it returns ErrHijackNotSupported if underlying ResponseWriter is not a http.Hijacker.
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.WithStack(ErrHijackNotSupported)
+	}
+
+	rw.headerWritten = true
+	return hijacker.Hijack()
+}
+
+// Unwrap returns underlying ResponseWriter.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// findResponseWriter unwraps rw until it finds *responseWriter.
+func findResponseWriter(rw http.ResponseWriter) (*responseWriter, bool) {
+	for {
+		if w, ok := rw.(*responseWriter); ok {
+			return w, true
+		}
+
+		unwrapper, ok := rw.(interface {
+			Unwrap() http.ResponseWriter
+		})
+		if !ok {
+			return nil, false
+		}
+		rw = unwrapper.Unwrap()
+	}
+}
+
+// Written reports whether response header was already written,
+// rw should be possessed from Handler registered on Router, otherwise Written returns false.
+// ResponseWriters wrapping the one passed to Handler should implement Unwrap() http.ResponseWriter method.
+func Written(rw http.ResponseWriter) bool {
+	w, ok := findResponseWriter(rw)
+	return ok && w.headerWritten
+}
+
+// StatusCode returns status code of written response or 0 if it wasn't written yet,
+// rw should be possessed from Handler registered on Router.
+func StatusCode(rw http.ResponseWriter) int {
+	w, ok := findResponseWriter(rw)
+	if !ok {
+		return 0
+	}
+	return w.statusCode
+}
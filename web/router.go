@@ -1,8 +1,10 @@
 package web
 
 import (
-	"fmt"
+	"context"
 	"net/http"
+	"sort"
+	"strings"
 
 	"github.com/corioders/gokit/log"
 	"github.com/dimfeld/httptreemux"
@@ -18,51 +20,105 @@ type Router interface {
 	http.Handler
 }
 
+type RouterOptions struct {
+	// ErrorHandler handles errors returned by handlers, if nil DefaultErrorHandler is used.
+	ErrorHandler ErrorHandler
+
+	// NotFound handles requests to paths without handlers, if nil response with status 404 is written.
+	NotFound Handler
+
+	// MethodNotAllowed handles requests to paths that have handlers, but not for the request's method,
+	// Allow header is set before it is called. If nil response with status 405 is written.
+	MethodNotAllowed Handler
+
+	// Panic handles panics recovered from handlers, recovered panic can be retrieved with PanicError.
+	// If nil panics are not recovered by router.
+	Panic Handler
+}
+
 type internalRouter struct {
-	logger log.Logger
+	errorHandler ErrorHandler
 
 	router     *httptreemux.ContextMux
 	middleware []Middleware
 }
 
-func NewRouter(logger log.Logger, middleware ...Middleware) Router {
-	return &internalRouter{
-		logger: logger,
+// NewRouter creates Router, options can be nil, middleware is used for all handlers,
+// including NotFound, MethodNotAllowed and Panic handlers from options.
+func NewRouter(logger log.Logger, options *RouterOptions, middleware ...Middleware) Router {
+	if options == nil {
+		options = &RouterOptions{}
+	}
+
+	ir := &internalRouter{
+		errorHandler: options.ErrorHandler,
 
 		router:     httptreemux.NewContextMux(),
 		middleware: middleware,
 	}
+	if ir.errorHandler == nil {
+		ir.errorHandler = DefaultErrorHandler(logger)
+	}
+
+	notFound := options.NotFound
+	if notFound == nil {
+		notFound = statusHandler(http.StatusNotFound)
+	}
+	ir.router.NotFoundHandler = newHTTPHandler(notFound, ir.errorHandler, middleware, nil)
+
+	methodNotAllowed := options.MethodNotAllowed
+	if methodNotAllowed == nil {
+		methodNotAllowed = statusHandler(http.StatusMethodNotAllowed)
+	}
+	methodNotAllowedHandler := newHTTPHandler(methodNotAllowed, ir.errorHandler, middleware, nil)
+	ir.router.MethodNotAllowedHandler = func(rw http.ResponseWriter, r *http.Request, methods map[string]httptreemux.HandlerFunc) {
+		allowed := make([]string, 0, len(methods))
+		for method := range methods {
+			allowed = append(allowed, method)
+		}
+		sort.Strings(allowed)
+
+		rw.Header().Set("Allow", strings.Join(allowed, ", "))
+		methodNotAllowedHandler(rw, r)
+	}
+
+	if options.Panic != nil {
+		ir.router.PanicHandler = newPanicHandler(options.Panic, ir.errorHandler, middleware)
+	}
+
+	return ir
 }
 
 // Handle registers handler on specified path and method
 func (ir *internalRouter) Handle(method string, path string, handler Handler, middleware ...Middleware) {
-	handle(ir.router, ir.logger, method, path, handler, ir.middleware, middleware)
+	handle(ir.router, ir.errorHandler, method, path, handler, ir.middleware, middleware)
 }
 
 // HandleAll registers handler on specified path and all of http methods
 func (ir *internalRouter) HandleAll(path string, handler Handler, middleware ...Middleware) {
-	handleAll(ir.router, ir.logger, path, handler, ir.middleware, middleware)
+	handleAll(ir.router, ir.errorHandler, path, handler, ir.middleware, middleware)
 }
 
 func (wr *internalRouter) NewGroup(path string, middleware ...Middleware) RouterGroup {
 	group := wr.router.NewContextGroup(path)
-	return newInternalGroup(wr.logger, path, group, middleware...)
+	return newInternalGroup(wr.errorHandler, path, group, middleware...)
 }
 
 func (ir *internalRouter) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
-	ir.router.ServeHTTP(rw, r)
+	// Response writer is wrapped here, so panic handler can see whether response was written.
+	ir.router.ServeHTTP(newResponseWriter(rw), r)
 }
 
 type internalGroup struct {
-	logger log.Logger
+	errorHandler ErrorHandler
 
 	group      *httptreemux.ContextGroup
 	middleware []Middleware
 }
 
-func newInternalGroup(logger log.Logger, path string, group *httptreemux.ContextGroup, middleware ...Middleware) *internalGroup {
+func newInternalGroup(errorHandler ErrorHandler, path string, group *httptreemux.ContextGroup, middleware ...Middleware) *internalGroup {
 	return &internalGroup{
-		logger: logger,
+		errorHandler: errorHandler,
 
 		group:      group,
 		middleware: middleware,
@@ -71,50 +127,61 @@ func newInternalGroup(logger log.Logger, path string, group *httptreemux.Context
 
 // Handle registers handler on specified path and method
 func (ig *internalGroup) Handle(method string, path string, handler Handler, middleware ...Middleware) {
-	handle(ig.group, ig.logger, method, path, handler, ig.middleware, middleware)
+	handle(ig.group, ig.errorHandler, method, path, handler, ig.middleware, middleware)
 }
 
 // HandleAll registers handler on specified path and all of http methods
 func (ig *internalGroup) HandleAll(path string, handler Handler, middleware ...Middleware) {
-	handleAll(ig.group, ig.logger, path, handler, ig.middleware, middleware)
+	handleAll(ig.group, ig.errorHandler, path, handler, ig.middleware, middleware)
 }
 
 func (ig *internalGroup) NewGroup(path string, middleware ...Middleware) RouterGroup {
 	group := ig.group.NewContextGroup(path)
-	return newInternalGroup(ig.logger, path, group, middleware...)
+	return newInternalGroup(ig.errorHandler, path, group, middleware...)
 }
 
 type router interface {
 	Handle(method, path string, handler http.HandlerFunc)
 }
 
-func handle(r router, logger log.Logger, method string, path string, handler Handler, generalMiddleware, specificMiddleware []Middleware) {
+func handle(r router, errorHandler ErrorHandler, method string, path string, handler Handler, generalMiddleware, specificMiddleware []Middleware) {
+	r.Handle(method, path, newHTTPHandler(handler, errorHandler, generalMiddleware, specificMiddleware))
+}
+
+// newHTTPHandler wraps middleware around handler and passes errors returned by it to errorHandler.
+func newHTTPHandler(handler Handler, errorHandler ErrorHandler, generalMiddleware, specificMiddleware []Middleware) http.HandlerFunc {
 	// First wrap handler specific middleware around this handler.
 	handler = wrapMiddleware(specificMiddleware, handler)
 
 	// Add the application's general middleware to the handler chain.
 	handler = wrapMiddleware(generalMiddleware, handler)
 
-	h := func(rw http.ResponseWriter, r *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		// Accept header is needed by Respond, which doesn't receive the request.
+		if accept := r.Header.Get("Accept"); accept != "" {
+			ctx = context.WithValue(ctx, ctxKeyAccept, accept)
+		}
+		wrw, ok := rw.(*responseWriter)
+		if !ok {
+			wrw = newResponseWriter(rw)
+		}
 
-		err := handler(ctx, rw, r)
+		err := handler(ctx, wrw, r)
 		if err != nil {
-			logger.Error(fmt.Sprintf("ERROR IN GOKIT WEB: %v", err))
+			errorHandler(ctx, wrw, r, err)
 		}
 	}
-
-	r.Handle(method, path, h)
 }
 
-func handleAll(r router, logger log.Logger, path string, handler Handler, generalMiddleware, specificMiddleware []Middleware) {
-	handle(r, logger, http.MethodGet, path, handler, generalMiddleware, specificMiddleware)
-	handle(r, logger, http.MethodHead, path, handler, generalMiddleware, specificMiddleware)
-	handle(r, logger, http.MethodPost, path, handler, generalMiddleware, specificMiddleware)
-	handle(r, logger, http.MethodPut, path, handler, generalMiddleware, specificMiddleware)
-	handle(r, logger, http.MethodPatch, path, handler, generalMiddleware, specificMiddleware)
-	handle(r, logger, http.MethodDelete, path, handler, generalMiddleware, specificMiddleware)
-	handle(r, logger, http.MethodConnect, path, handler, generalMiddleware, specificMiddleware)
-	handle(r, logger, http.MethodOptions, path, handler, generalMiddleware, specificMiddleware)
-	handle(r, logger, http.MethodTrace, path, handler, generalMiddleware, specificMiddleware)
+func handleAll(r router, errorHandler ErrorHandler, path string, handler Handler, generalMiddleware, specificMiddleware []Middleware) {
+	handle(r, errorHandler, http.MethodGet, path, handler, generalMiddleware, specificMiddleware)
+	handle(r, errorHandler, http.MethodHead, path, handler, generalMiddleware, specificMiddleware)
+	handle(r, errorHandler, http.MethodPost, path, handler, generalMiddleware, specificMiddleware)
+	handle(r, errorHandler, http.MethodPut, path, handler, generalMiddleware, specificMiddleware)
+	handle(r, errorHandler, http.MethodPatch, path, handler, generalMiddleware, specificMiddleware)
+	handle(r, errorHandler, http.MethodDelete, path, handler, generalMiddleware, specificMiddleware)
+	handle(r, errorHandler, http.MethodConnect, path, handler, generalMiddleware, specificMiddleware)
+	handle(r, errorHandler, http.MethodOptions, path, handler, generalMiddleware, specificMiddleware)
+	handle(r, errorHandler, http.MethodTrace, path, handler, generalMiddleware, specificMiddleware)
 }
@@ -0,0 +1,114 @@
+package web
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corioders/gokit/errors"
+	"github.com/corioders/gokit/log"
+)
+
+func TestRouter(t *testing.T) {
+	logger := log.New(io.Discard, "")
+
+	// headerMiddleware marks responses, so it can be checked that handler received router's middleware.
+	headerMiddleware := func(handler Handler) Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+			rw.Header().Set("X-Middleware", "true")
+			return handler(ctx, rw, r)
+		}
+	}
+
+	t.Run("default error handler", func(t *testing.T) {
+		router := NewRouter(logger, nil)
+		router.Handle(http.MethodGet, "/", func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+			return errors.WithKind(errors.New("Not found"), errors.KindNotFound)
+		})
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != http.StatusNotFound {
+			t.Fatal("Expected status of error kind, but got:", rec.Code)
+		}
+	})
+
+	t.Run("error handler", func(t *testing.T) {
+		var handledErr error
+		router := NewRouter(logger, &RouterOptions{
+			ErrorHandler: func(ctx context.Context, rw http.ResponseWriter, r *http.Request, err error) {
+				handledErr = err
+				rw.WriteHeader(http.StatusTeapot)
+			},
+		})
+
+		expectedErr := errors.New("Test error")
+		router.Handle(http.MethodGet, "/", func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+			return expectedErr
+		})
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if handledErr != expectedErr || rec.Code != http.StatusTeapot {
+			t.Fatal("Expected error handler to decide response, but got:", rec.Code, handledErr)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		router := NewRouter(logger, &RouterOptions{
+			NotFound: func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+				rw.WriteHeader(http.StatusGone)
+				return nil
+			},
+		}, headerMiddleware)
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+		if rec.Code != http.StatusGone || rec.Header().Get("X-Middleware") != "true" {
+			t.Fatal("Expected not found handler to be called with router's middleware, but got:", rec.Code, rec.Header())
+		}
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		router := NewRouter(logger, nil, headerMiddleware)
+		router.Handle(http.MethodGet, "/", func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+			return nil
+		})
+		router.Handle(http.MethodPost, "/", func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+			return nil
+		})
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/", nil))
+		if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("X-Middleware") != "true" {
+			t.Fatal("Expected method not allowed response with router's middleware, but got:", rec.Code, rec.Header())
+		}
+		if rec.Header().Get("Allow") != "GET, HEAD, POST" {
+			t.Fatal("Expected Allow header with allowed methods, but got:", rec.Header().Get("Allow"))
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		var panicErr error
+		router := NewRouter(logger, &RouterOptions{
+			Panic: func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+				panicErr = PanicError(ctx)
+				return panicErr
+			},
+		})
+		router.Handle(http.MethodGet, "/", func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+			panic("test panic")
+		})
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if panicErr == nil || errors.KindOf(panicErr) != errors.KindInternal {
+			t.Fatal("Expected panic handler to receive recovered panic, but got:", panicErr)
+		}
+		if rec.Code != http.StatusInternalServerError {
+			t.Fatal("Expected error returned by panic handler to be handled, but got:", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"github.com/corioders/gokit/application"
+	"github.com/corioders/gokit/errors"
+	"github.com/corioders/gokit/log"
+)
+
+// Group is set of servers started and stopped together,
+// for example public server and admin server with metrics and health checks.
+type Group struct {
+	servers []*Server
+}
+
+// NewGroup starts servers for all options, each server logs with child of logger prefixed with its name.
+// Servers start serving only after all of them are listening, if any of them fails to listen none is started.
+// Servers are shut down in reverse order of options when application is stopping.
+func NewGroup(sr application.StopRegistrar, logger log.Logger, options ...*Options) (*Group, error) {
+	err := validateGroup(options)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Group{}
+	for _, o := range options {
+		s, err := listen(logger, o)
+		if err != nil {
+			return nil, errors.Append(errors.WithMessage(err, o.Name), g.close())
+		}
+		g.servers = append(g.servers, s)
+	}
+
+	for i, s := range g.servers {
+		err := s.start(sr, options[i])
+		if err != nil {
+			// Servers that already started are shut down by application.
+			var errs errors.Collector
+			errs.Append(err)
+			for _, s := range g.servers[i:] {
+				errs.Append(s.listener.Close())
+			}
+			return nil, errs.Err()
+		}
+	}
+
+	return g, nil
+}
+
+func validateGroup(options []*Options) error {
+	var err error
+	names := map[string]struct{}{}
+	for _, o := range options {
+		if o.Name == "" {
+			err = errors.Append(err, errors.WithStack(ErrNameNotProvided))
+			continue
+		}
+
+		if _, ok := names[o.Name]; ok {
+			err = errors.Append(err, errors.With(ErrDuplicateName, "name", o.Name))
+		}
+		names[o.Name] = struct{}{}
+	}
+
+	return err
+}
+
+// close closes listeners of servers that didn't start serving.
+func (g *Group) close() error {
+	var errs errors.Collector
+	for _, s := range g.servers {
+		errs.Append(s.listener.Close())
+	}
+	return errs.Err()
+}
+
+// Server returns server with name or nil if there is no such server.
+func (g *Group) Server(name string) *Server {
+	for _, s := range g.servers {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// Servers returns servers in order of their options.
+func (g *Group) Servers() []*Server {
+	return append([]*Server(nil), g.servers...)
+}
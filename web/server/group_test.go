@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/corioders/gokit/application"
+	"github.com/corioders/gokit/errors"
+	"github.com/corioders/gokit/log"
+	"github.com/corioders/gokit/log/logtest"
+	"github.com/corioders/gokit/web"
+)
+
+func TestGroup(t *testing.T) {
+	t.Run("validation", func(t *testing.T) {
+		logger := logtest.New(t)
+		app := application.New(logger)
+		_, err := NewGroup(app, logger,
+			&Options{Router: newTestRouter(logger)},
+			&Options{Name: "public", Router: newTestRouter(logger)},
+			&Options{Name: "public", Router: newTestRouter(logger)},
+		)
+		if !errors.Is(err, ErrNameNotProvided) || !errors.Is(err, ErrDuplicateName) {
+			t.Fatal("Expected all validation errors, but got:", err)
+		}
+	})
+
+	t.Run("public and admin", func(t *testing.T) {
+		logger := logtest.New(t)
+		app := application.New(logger)
+
+		admin := web.NewRouter(logger, nil)
+		admin.Handle(http.MethodGet, "/", func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+			_, err := rw.Write([]byte("admin"))
+			return err
+		})
+
+		g, err := NewGroup(app, logger,
+			&Options{Name: "public", Router: newTestRouter(logger), Listener: TCP("127.0.0.1:0")},
+			&Options{Name: "admin", Router: admin, Listener: TCP("127.0.0.1:0")},
+		)
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		if get(t, http.DefaultClient, "http://"+g.Server("public").Addr().String()) != "ok" {
+			t.Fatal("Expected public router to be served on public listener")
+		}
+		if get(t, http.DefaultClient, "http://"+g.Server("admin").Addr().String()) != "admin" {
+			t.Fatal("Expected admin router to be served on admin listener")
+		}
+
+		entries := logger.Filter(func(entry *log.Entry) bool {
+			return len(entry.Prefixes) == 1 && (entry.Prefixes[0] == "public" || entry.Prefixes[0] == "admin")
+		})
+		if len(entries) != 2 {
+			t.Fatal("Expected each server to log with its own child logger, but got:", logger.String())
+		}
+
+		err = app.Stop()
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		for _, s := range g.Servers() {
+			select {
+			case <-s.Done():
+			default:
+				t.Fatal("Expected all servers to stop serving after application stopped")
+			}
+		}
+		logger.AssertMessage("Stopped successfully HTTP server public...")
+		logger.AssertMessage("Stopped successfully HTTP server admin...")
+	})
+
+	t.Run("listen failure", func(t *testing.T) {
+		logger := logtest.New(t)
+		app := application.New(logger)
+		defer app.Stop()
+
+		s, err := New(app, logger, &Options{Router: newTestRouter(logger), Listener: TCP("127.0.0.1:0")})
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		_, err = NewGroup(app, logger,
+			&Options{Name: "public", Router: newTestRouter(logger), Listener: TCP("127.0.0.1:0")},
+			&Options{Name: "admin", Router: newTestRouter(logger), Listener: TCP(s.Addr().String())},
+		)
+		if err == nil {
+			t.Fatal("Expected error when listener address is in use")
+		}
+		if len(logger.Filter(func(entry *log.Entry) bool { return len(entry.Prefixes) == 1 && entry.Prefixes[0] == "public" })) != 0 {
+			t.Fatal("Expected no server to start when one of them fails to listen, but got:", logger.String())
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+
+	"github.com/corioders/gokit/errors"
+)
+
+// Listener creates net.Listener on which server accepts connections.
+type Listener interface {
+	Listen() (net.Listener, error)
+	String() string
+}
+
+// TCP returns Listener listening on tcp network address addr, for example ":8080".
+func TCP(addr string) Listener {
+	return &tcpListener{addr: addr}
+}
+
+type tcpListener struct {
+	addr string
+}
+
+func (l *tcpListener) Listen() (net.Listener, error) {
+	ln, err := net.Listen("tcp", l.addr)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return ln, nil
+}
+
+func (l *tcpListener) String() string {
+	return "tcp " + l.addr
+}
+
+// Unix returns Listener listening on unix socket at path, stale socket left at path is removed,
+// but socket on which other process accepts connections is not, ErrSocketInUse is returned then.
+func Unix(path string) Listener {
+	return &unixListener{path: path}
+}
+
+type unixListener struct {
+	path string
+}
+
+func (l *unixListener) Listen() (net.Listener, error) {
+	info, err := os.Stat(l.path)
+	if err == nil && info.Mode()&os.ModeSocket != 0 {
+		err = l.removeStale()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ln, err := net.Listen("unix", l.path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return ln, nil
+}
+
+// removeStale removes socket at l.path if nothing accepts connections on it.
+func (l *unixListener) removeStale() error {
+	conn, err := net.Dial("unix", l.path)
+	if err == nil {
+		conn.Close()
+		return errors.WithMessage(ErrSocketInUse, l.path)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return errors.WithStack(err)
+	}
+
+	err = os.Remove(l.path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (l *unixListener) String() string {
+	return "unix " + l.path
+}
+
+// FD returns Listener using already opened socket with file descriptor fd,
+// for example one passed by systemd socket activation or by parent process during graceful restart.
+func FD(fd uintptr) Listener {
+	return &fdListener{fd: fd}
+}
+
+type fdListener struct {
+	fd uintptr
+}
+
+func (l *fdListener) Listen() (net.Listener, error) {
+	f := os.NewFile(l.fd, l.String())
+	if f == nil {
+		return nil, errors.WithMessage(ErrInvalidFD, l.String())
+	}
+	// FileListener duplicates the descriptor, so f isn't needed after it.
+	defer f.Close()
+
+	ln, err := net.FileListener(f)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return ln, nil
+}
+
+func (l *fdListener) String() string {
+	return fmt.Sprintf("fd %d", l.fd)
+}
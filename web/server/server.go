@@ -1,7 +1,272 @@
+// Package server provides http server serving web.Router, which is stopped together with application.
 package server
 
-type Options struct{}
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
 
-func New(options *Options) {
+	"github.com/corioders/gokit/application"
+	"github.com/corioders/gokit/errors"
+	"github.com/corioders/gokit/log"
+	"github.com/corioders/gokit/web"
+)
 
+// Default timeouts used when corresponding option is zero.
+const (
+	DefaultReadTimeout       = 30 * time.Second
+	DefaultReadHeaderTimeout = 10 * time.Second
+	DefaultWriteTimeout      = 30 * time.Second
+	DefaultIdleTimeout       = 120 * time.Second
+)
+
+type Options struct {
+	// Name identifies the server in logs and stop handlers, it is required by NewGroup.
+	Name string
+
+	Router web.Router
+
+	// Listener is the listener on which server accepts connections, if nil TCP(":http") or TCP(":https") is used.
+	Listener Listener
+
+	// Timeouts have the same meaning as in http.Server, zero means the default timeout, negative means no timeout.
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+
+	// MaxHeaderBytes limits size of request headers, zero means http.DefaultMaxHeaderBytes.
+	MaxHeaderBytes int
+
+	// TLS enables serving https.
+	TLS *TLSOptions
+
+	// ShutdownTimeout is the time in which active connections must be drained while application is stopping,
+	// after that they are closed forcibly. Zero means that draining is bounded only by application's stop deadline.
+	ShutdownTimeout time.Duration
+}
+
+func (o *Options) validate() error {
+	var err error
+	if o.Router == nil {
+		err = errors.Append(err, errors.WithStack(ErrRouterNotProvided))
+	}
+	if o.TLS != nil {
+		err = errors.Append(err, o.TLS.validate())
+	}
+
+	return err
+}
+
+var (
+	ErrRouterNotProvided         = errors.New("Router not provided")
+	ErrTLSCertificateNotProvided = errors.New("TLS certificate not provided")
+	ErrTLSKeyPairNotProvided     = errors.New("TLS certificate and key files must be provided together")
+	ErrTLSNotEnabled             = errors.New("TLS not enabled")
+	ErrInvalidFD                 = errors.New("Invalid file descriptor")
+	ErrNameNotProvided           = errors.New("Server name not provided")
+	ErrDuplicateName             = errors.New("Duplicate server name")
+	ErrSocketInUse               = errors.New("Unix socket is in use")
+)
+
+// Server is running http server.
+type Server struct {
+	name   string
+	logger log.Logger
+
+	server       *http.Server
+	listener     net.Listener
+	certificates *certificates
+
+	// done is closed when server stops serving, serveErr is set before that.
+	done     chan struct{}
+	serveErr error
+	// stopReload stops reloading certificates, it is closed once by shutdown.
+	stopReload     chan struct{}
+	stopReloadOnce sync.Once
+}
+
+// New starts listening and serving options.Router, server is shut down when application is stopping.
+// If options.Name is set, server logs with child of logger with that prefix.
+func New(sr application.StopRegistrar, logger log.Logger, options *Options) (*Server, error) {
+	s, err := listen(logger, options)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.start(sr, options)
+	if err != nil {
+		return nil, errors.Append(err, s.listener.Close())
+	}
+	return s, nil
+}
+
+// listen creates server and its listener, but doesn't start serving.
+func listen(logger log.Logger, options *Options) (*Server, error) {
+	err := options.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Name != "" {
+		logger = logger.Child(options.Name)
+	}
+
+	s := &Server{
+		name:   options.Name,
+		logger: logger,
+
+		done:       make(chan struct{}),
+		stopReload: make(chan struct{}),
+	}
+
+	s.server = &http.Server{
+		Handler:           options.Router,
+		ReadTimeout:       timeout(options.ReadTimeout, DefaultReadTimeout),
+		ReadHeaderTimeout: timeout(options.ReadHeaderTimeout, DefaultReadHeaderTimeout),
+		WriteTimeout:      timeout(options.WriteTimeout, DefaultWriteTimeout),
+		IdleTimeout:       timeout(options.IdleTimeout, DefaultIdleTimeout),
+		MaxHeaderBytes:    options.MaxHeaderBytes,
+		ErrorLog:          log.NewStdLogger(logger, log.LevelWarn),
+	}
+
+	listener := options.Listener
+	if options.TLS != nil {
+		s.certificates, err = newCertificates(options.TLS)
+		if err != nil {
+			return nil, err
+		}
+		s.server.TLSConfig = s.certificates.tlsConfig()
+
+		if listener == nil {
+			listener = TCP(":https")
+		}
+	}
+	if listener == nil {
+		listener = TCP(":http")
+	}
+
+	if s.name == "" {
+		s.name = listener.String()
+	}
+
+	s.listener, err = listener.Listen()
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// start registers shutdown of the server and starts serving.
+func (s *Server) start(sr application.StopRegistrar, options *Options) error {
+	err := sr.RegisterOnStopContext(fmt.Sprintf("HTTP server %s", s.name), s.shutdown, application.WithStopTimeout(options.ShutdownTimeout))
+	if err != nil {
+		return err
+	}
+
+	go s.serve()
+	if options.TLS != nil && options.TLS.fromFiles() && options.TLS.ReloadInterval > 0 {
+		go s.reloadTLS(options.TLS.ReloadInterval)
+	}
+
+	s.logger.Info(fmt.Sprintf("Listening on %s", s.listener.Addr()))
+	return nil
+}
+
+// timeout returns default if t is zero and no timeout if t is negative.
+func timeout(t time.Duration, defaultTimeout time.Duration) time.Duration {
+	if t == 0 {
+		return defaultTimeout
+	}
+	if t < 0 {
+		return 0
+	}
+	return t
+}
+
+func (s *Server) serve() {
+	defer close(s.done)
+
+	var err error
+	if s.certificates != nil {
+		err = s.server.ServeTLS(s.listener, "", "")
+	} else {
+		err = s.server.Serve(s.listener)
+	}
+
+	if err != nil && err != http.ErrServerClosed {
+		s.serveErr = errors.WithStack(err)
+		s.logger.Error(s.serveErr)
+	}
+}
+
+func (s *Server) reloadTLS(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := s.certificates.reloadIfModified()
+			if err != nil {
+				s.logger.Error(errors.WithMessage(err, "Reloading TLS certificate"))
+			}
+		case <-s.stopReload:
+			return
+		}
+	}
+}
+
+// shutdown gracefully shuts down the server, if ctx is done before connections are drained they are closed.
+func (s *Server) shutdown(ctx context.Context) error {
+	s.stopReloadOnce.Do(func() { close(s.stopReload) })
+
+	err := s.server.Shutdown(ctx)
+	if err != nil {
+		err = errors.Append(errors.WithStack(err), s.server.Close())
+	}
+
+	<-s.done
+	return errors.Append(err, s.serveErr)
+}
+
+// Name returns name of the server, if it wasn't provided in options it is description of the listener.
+func (s *Server) Name() string {
+	return s.name
+}
+
+// Addr returns address on which server is listening.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Done returns channel that is closed when server stops serving.
+func (s *Server) Done() <-chan struct{} {
+	return s.done
+}
+
+// ReloadTLS reads certificate and key files again.
+func (s *Server) ReloadTLS() error {
+	if s.certificates == nil {
+		return ErrTLSNotEnabled
+	}
+	if !s.certificates.options.fromFiles() {
+		return nil
+	}
+
+	return s.certificates.load()
+}
+
+// SetCertificates replaces certificates served by the server.
+func (s *Server) SetCertificates(certs []tls.Certificate) error {
+	if s.certificates == nil {
+		return ErrTLSNotEnabled
+	}
+
+	s.certificates.set(certs)
+	return nil
 }
@@ -0,0 +1,238 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/corioders/gokit/application"
+	"github.com/corioders/gokit/errors"
+	"github.com/corioders/gokit/log"
+	"github.com/corioders/gokit/web"
+)
+
+func newTestRouter(logger log.Logger) web.Router {
+	router := web.NewRouter(logger, nil)
+	router.Handle(http.MethodGet, "/", func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+		_, err := rw.Write([]byte("ok"))
+		return err
+	})
+	router.Handle(http.MethodGet, "/slow", func(ctx context.Context, rw http.ResponseWriter, r *http.Request) error {
+		time.Sleep(100 * time.Millisecond)
+		_, err := rw.Write([]byte("slow"))
+		return err
+	})
+	return router
+}
+
+func newTestCertificate(t *testing.T, commonName string) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal("Expected no error, but got:", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal("Expected no error, but got:", err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func get(t *testing.T, client *http.Client, url string) string {
+	res, err := client.Get(url)
+	if err != nil {
+		t.Fatal("Expected no error, but got:", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal("Expected no error, but got:", err)
+	}
+	return string(body)
+}
+
+func TestServer(t *testing.T) {
+	logger := log.New(io.Discard, "")
+
+	t.Run("validation", func(t *testing.T) {
+		app := application.New(logger)
+		_, err := New(app, logger, &Options{TLS: &TLSOptions{CertFile: "cert.pem"}})
+		if !errors.Is(err, ErrRouterNotProvided) || !errors.Is(err, ErrTLSKeyPairNotProvided) {
+			t.Fatal("Expected all validation errors, but got:", err)
+		}
+	})
+
+	t.Run("graceful shutdown", func(t *testing.T) {
+		app := application.New(logger)
+		s, err := New(app, logger, &Options{Router: newTestRouter(logger), Listener: TCP("127.0.0.1:0")})
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		url := "http://" + s.Addr().String()
+
+		if get(t, http.DefaultClient, url) != "ok" {
+			t.Fatal("Expected router to be served")
+		}
+
+		slow := make(chan string)
+		go func() {
+			res, err := http.Get(url + "/slow")
+			if err != nil {
+				slow <- err.Error()
+				return
+			}
+			defer res.Body.Close()
+			body, _ := io.ReadAll(res.Body)
+			slow <- string(body)
+		}()
+		// Let the slow request reach the handler.
+		time.Sleep(20 * time.Millisecond)
+
+		err = app.Stop()
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		if body := <-slow; body != "slow" {
+			t.Fatal("Expected in-flight request to be drained, but got:", body)
+		}
+
+		select {
+		case <-s.Done():
+		default:
+			t.Fatal("Expected server to stop serving after application stopped")
+		}
+		_, err = http.Get(url)
+		if err == nil {
+			t.Fatal("Expected server not to accept connections after application stopped")
+		}
+	})
+
+	t.Run("drain deadline", func(t *testing.T) {
+		app := application.New(logger)
+		s, err := New(app, logger, &Options{Router: newTestRouter(logger), Listener: TCP("127.0.0.1:0"), ShutdownTimeout: time.Millisecond})
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		go http.Get("http://" + s.Addr().String() + "/slow")
+		time.Sleep(20 * time.Millisecond)
+
+		err = app.Stop()
+		if !errors.Is(err, application.ErrStopTimeout) {
+			t.Fatal("Expected ErrStopTimeout when connections are not drained in time, but got:", err)
+		}
+	})
+
+	t.Run("unix", func(t *testing.T) {
+		app := application.New(logger)
+		defer app.Stop()
+
+		path := filepath.Join(t.TempDir(), "server.sock")
+		_, err := New(app, logger, &Options{Router: newTestRouter(logger), Listener: Unix(path)})
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		client := &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", path)
+			},
+		}}
+		if get(t, client, "http://unix/") != "ok" {
+			t.Fatal("Expected router to be served on unix socket")
+		}
+
+		_, err = New(app, logger, &Options{Router: newTestRouter(logger), Listener: Unix(path)})
+		if !errors.Is(err, ErrSocketInUse) {
+			t.Fatal("Expected ErrSocketInUse when socket accepts connections, but got:", err)
+		}
+	})
+
+	t.Run("unix stale socket", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "server.sock")
+		ln, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		ln.SetUnlinkOnClose(false)
+		ln.Close()
+
+		ln2, err := Unix(path).Listen()
+		if err != nil {
+			t.Fatal("Expected stale socket to be removed, but got:", err)
+		}
+		ln2.Close()
+	})
+
+	t.Run("shutdown twice", func(t *testing.T) {
+		app := application.New(logger)
+		s, err := New(app, logger, &Options{Router: newTestRouter(logger), Listener: TCP("127.0.0.1:0")})
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		err = app.Stop()
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		err = s.shutdown(context.Background())
+		if err != nil {
+			t.Fatal("Expected shutting down stopped server to succeed, but got:", err)
+		}
+	})
+
+	t.Run("tls", func(t *testing.T) {
+		app := application.New(logger)
+		defer app.Stop()
+
+		s, err := New(app, logger, &Options{
+			Router:   newTestRouter(logger),
+			Listener: TCP("127.0.0.1:0"),
+			TLS:      &TLSOptions{Certificates: []tls.Certificate{newTestCertificate(t, "first")}},
+		})
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+
+		commonName := func() string {
+			conn, err := tls.Dial("tcp", s.Addr().String(), &tls.Config{InsecureSkipVerify: true})
+			if err != nil {
+				t.Fatal("Expected no error, but got:", err)
+			}
+			defer conn.Close()
+			return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+		}
+
+		if commonName() != "first" {
+			t.Fatal("Expected provided certificate to be served")
+		}
+
+		err = s.SetCertificates([]tls.Certificate{newTestCertificate(t, "second")})
+		if err != nil {
+			t.Fatal("Expected no error, but got:", err)
+		}
+		if commonName() != "second" {
+			t.Fatal("Expected replaced certificate to be served")
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/corioders/gokit/errors"
+)
+
+type TLSOptions struct {
+	// CertFile and KeyFile are paths of PEM encoded certificate and its key.
+	CertFile string
+	KeyFile  string
+
+	// Certificates are used when CertFile and KeyFile are not provided,
+	// they can be replaced while server is running with Server.SetCertificates.
+	Certificates []tls.Certificate
+
+	// ReloadInterval is interval in which CertFile and KeyFile are checked for modification and reloaded,
+	// zero means that files are reloaded only by Server.ReloadTLS.
+	ReloadInterval time.Duration
+
+	// Config is base tls configuration, its certificates are ignored,
+	// if nil configuration with minimum version TLS 1.2 is used.
+	Config *tls.Config
+}
+
+func (o *TLSOptions) validate() error {
+	if o.CertFile == "" && o.KeyFile == "" && len(o.Certificates) == 0 {
+		return ErrTLSCertificateNotProvided
+	}
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		return ErrTLSKeyPairNotProvided
+	}
+
+	return nil
+}
+
+func (o *TLSOptions) fromFiles() bool {
+	return o.CertFile != ""
+}
+
+// certificates holds certificates served by server, they can be replaced while server is running.
+type certificates struct {
+	options *TLSOptions
+
+	mu      sync.RWMutex
+	certs   []tls.Certificate
+	modTime time.Time
+}
+
+func newCertificates(options *TLSOptions) (*certificates, error) {
+	c := &certificates{options: options}
+	if !options.fromFiles() {
+		c.set(options.Certificates)
+		return c, nil
+	}
+
+	err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// load reads certificate from files.
+func (c *certificates) load() error {
+	modTime, err := c.filesModTime()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.options.CertFile, c.options.KeyFile)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.certs = []tls.Certificate{cert}
+	c.modTime = modTime
+	return nil
+}
+
+// reloadIfModified reads certificate from files if any of them was modified since last load.
+func (c *certificates) reloadIfModified() error {
+	modTime, err := c.filesModTime()
+	if err != nil {
+		return err
+	}
+
+	c.mu.RLock()
+	modified := modTime.After(c.modTime)
+	c.mu.RUnlock()
+	if !modified {
+		return nil
+	}
+	return c.load()
+}
+
+// filesModTime returns the latest modification time of certificate and key files.
+func (c *certificates) filesModTime() (time.Time, error) {
+	var modTime time.Time
+	for _, path := range []string{c.options.CertFile, c.options.KeyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, errors.WithStack(err)
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+	return modTime, nil
+}
+
+func (c *certificates) set(certs []tls.Certificate) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.certs = append([]tls.Certificate(nil), certs...)
+}
+
+// getCertificate is used as tls.Config.GetCertificate,
+// it returns the first certificate supported by client or the first certificate if none is supported.
+func (c *certificates) getCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.certs) == 0 {
+		return nil, ErrTLSCertificateNotProvided
+	}
+
+	for i := range c.certs {
+		if hello.SupportsCertificate(&c.certs[i]) == nil {
+			return &c.certs[i], nil
+		}
+	}
+	return &c.certs[0], nil
+}
+
+func (c *certificates) tlsConfig() *tls.Config {
+	var config *tls.Config
+	if c.options.Config != nil {
+		config = c.options.Config.Clone()
+	} else {
+		config = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	config.Certificates = nil
+	config.GetCertificate = c.getCertificate
+	return config
+}